@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/0x4d31/galah/logging"
+	"github.com/0x4d31/galah/output"
+	"github.com/0x4d31/galah/template"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the top-level structure of config.yaml.
+type Config struct {
+	Provider       string                 `yaml:"provider"`
+	Model          string                 `yaml:"model"`
+	APIKey         string                 `yaml:"api_key"`
+	Endpoint       string                 `yaml:"endpoint"`
+	PromptTemplate string                 `yaml:"prompt_template"`
+	CacheDuration  int                    `yaml:"cache_duration"`
+	Ports          []PortConfig           `yaml:"ports"`
+	TLS            map[string]TLSConfig   `yaml:"tls"`
+	Output         output.Config          `yaml:"output"`
+	Templates      []template.RouteConfig `yaml:"templates"`
+	Logging        logging.Config         `yaml:"logging"`
+}
+
+// PortConfig describes a single listener galah should serve.
+type PortConfig struct {
+	Port       uint16 `yaml:"port"`
+	Protocol   string `yaml:"protocol"`
+	TLSProfile string `yaml:"tlsProfile"`
+}
+
+// TLSConfig is a named certificate/key pair referenced by PortConfig.TLSProfile.
+type TLSConfig struct {
+	Certificate string `yaml:"certificate"`
+	Key         string `yaml:"key"`
+}
+
+// LoadConfig reads and parses the config file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	return &cfg, nil
+}
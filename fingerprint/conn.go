@@ -0,0 +1,44 @@
+package fingerprint
+
+import (
+	"net"
+	"sync"
+)
+
+// conn wraps a net.Conn and tees every byte read from it into buf, so the
+// raw ClientHello record can be recovered after crypto/tls has consumed
+// it during the handshake.
+type conn struct {
+	net.Conn
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (c *conn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.mu.Lock()
+		// The ClientHello is always the first bytes read on a fresh
+		// connection; stop capturing once we have more than enough for
+		// it so a long-lived HTTP/2 connection doesn't grow buf forever.
+		if len(c.buf) < maxCaptureBytes {
+			c.buf = append(c.buf, p[:n]...)
+		}
+		c.mu.Unlock()
+	}
+	return n, err
+}
+
+// maxCaptureBytes bounds how much of a connection's traffic is buffered
+// for fingerprinting; a real ClientHello is at most a few KB.
+const maxCaptureBytes = 16 * 1024
+
+// captured returns the bytes read from the connection so far.
+func (c *conn) captured() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]byte, len(c.buf))
+	copy(out, c.buf)
+	return out
+}
@@ -0,0 +1,271 @@
+// Package fingerprint computes JA3 and JA4 TLS client fingerprints from a
+// captured ClientHello, so galah can tag a connection by its TLS stack
+// (curl, a browser, a scanner's custom client) instead of only by source
+// IP and User-Agent, which sophisticated scanners rotate freely.
+package fingerprint
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Extension IDs referenced while parsing; see the TLS IANA registry.
+const (
+	extServerName            = 0x0000
+	extSupportedGroups       = 0x000a
+	extECPointFormats        = 0x000b
+	extSignatureAlgorithms   = 0x000d
+	extALPN                  = 0x0010
+	handshakeTypeClientHello = 0x01
+	recordTypeHandshake      = 0x16
+)
+
+// ClientHello holds the fields of a parsed TLS ClientHello that JA3 and
+// JA4 are derived from.
+type ClientHello struct {
+	Version             uint16
+	CipherSuites        []uint16
+	Extensions          []uint16
+	SupportedGroups     []uint16
+	ECPointFormats      []byte
+	SignatureAlgorithms []uint16
+	ALPN                []string
+	ServerName          string
+}
+
+// Parse extracts a ClientHello from raw, the bytes of a single captured
+// TLS record. It assumes the ClientHello fits in one TLS record (true
+// for the overwhelming majority of real-world clients and scanners);
+// a ClientHello fragmented across records is reported as a parse error
+// rather than reassembled.
+func Parse(raw []byte) (*ClientHello, error) {
+	if len(raw) < 9 || raw[0] != recordTypeHandshake {
+		return nil, errors.New("fingerprint: not a TLS handshake record")
+	}
+	fragLen := int(binary.BigEndian.Uint16(raw[3:5]))
+	if len(raw) < 5+fragLen {
+		return nil, errors.New("fingerprint: truncated TLS record")
+	}
+	body := raw[5 : 5+fragLen]
+
+	if len(body) < 4 || body[0] != handshakeTypeClientHello {
+		return nil, errors.New("fingerprint: not a ClientHello")
+	}
+	msgLen := int(body[1])<<16 | int(body[2])<<8 | int(body[3])
+	if len(body) < 4+msgLen {
+		return nil, errors.New("fingerprint: truncated ClientHello")
+	}
+	p := body[4 : 4+msgLen]
+
+	ch := &ClientHello{}
+
+	if len(p) < 2+32+1 {
+		return nil, errors.New("fingerprint: ClientHello too short")
+	}
+	ch.Version = binary.BigEndian.Uint16(p[0:2])
+	p = p[2+32:] // skip client_version and random
+
+	sessIDLen := int(p[0])
+	p = p[1:]
+	if len(p) < sessIDLen {
+		return nil, errors.New("fingerprint: truncated session id")
+	}
+	p = p[sessIDLen:]
+
+	if len(p) < 2 {
+		return nil, errors.New("fingerprint: truncated cipher suites")
+	}
+	cipherLen := int(binary.BigEndian.Uint16(p[0:2]))
+	p = p[2:]
+	if len(p) < cipherLen {
+		return nil, errors.New("fingerprint: truncated cipher suites")
+	}
+	for i := 0; i+1 < cipherLen; i += 2 {
+		ch.CipherSuites = append(ch.CipherSuites, binary.BigEndian.Uint16(p[i:i+2]))
+	}
+	p = p[cipherLen:]
+
+	if len(p) < 1 {
+		return nil, errors.New("fingerprint: truncated compression methods")
+	}
+	compLen := int(p[0])
+	p = p[1:]
+	if len(p) < compLen {
+		return nil, errors.New("fingerprint: truncated compression methods")
+	}
+	p = p[compLen:]
+
+	if len(p) == 0 {
+		// No extensions block; a bare ClientHello is unusual but valid.
+		return ch, nil
+	}
+	if len(p) < 2 {
+		return nil, errors.New("fingerprint: truncated extensions")
+	}
+	extLen := int(binary.BigEndian.Uint16(p[0:2]))
+	p = p[2:]
+	if len(p) < extLen {
+		return nil, errors.New("fingerprint: truncated extensions")
+	}
+	p = p[:extLen]
+
+	for len(p) >= 4 {
+		extType := binary.BigEndian.Uint16(p[0:2])
+		extDataLen := int(binary.BigEndian.Uint16(p[2:4]))
+		p = p[4:]
+		if len(p) < extDataLen {
+			return nil, errors.New("fingerprint: truncated extension data")
+		}
+		data := p[:extDataLen]
+		p = p[extDataLen:]
+
+		ch.Extensions = append(ch.Extensions, extType)
+		switch extType {
+		case extServerName:
+			ch.ServerName = parseServerName(data)
+		case extSupportedGroups:
+			ch.SupportedGroups = parseUint16List(data)
+		case extECPointFormats:
+			if len(data) >= 1 {
+				n := int(data[0])
+				if len(data) >= 1+n {
+					ch.ECPointFormats = append([]byte(nil), data[1:1+n]...)
+				}
+			}
+		case extSignatureAlgorithms:
+			ch.SignatureAlgorithms = parseUint16List(data)
+		case extALPN:
+			ch.ALPN = parseALPN(data)
+		}
+	}
+
+	return ch, nil
+}
+
+// parseUint16List parses a TLS "length-prefixed list of uint16" body, as
+// used by supported_groups and signature_algorithms.
+func parseUint16List(data []byte) []uint16 {
+	if len(data) < 2 {
+		return nil
+	}
+	n := int(binary.BigEndian.Uint16(data[0:2]))
+	data = data[2:]
+	if len(data) < n {
+		return nil
+	}
+	var out []uint16
+	for i := 0; i+1 < n; i += 2 {
+		out = append(out, binary.BigEndian.Uint16(data[i:i+2]))
+	}
+	return out
+}
+
+func parseServerName(data []byte) string {
+	if len(data) < 2 {
+		return ""
+	}
+	listLen := int(binary.BigEndian.Uint16(data[0:2]))
+	data = data[2:]
+	if len(data) < listLen || listLen < 3 {
+		return ""
+	}
+	// name_type(1) + name length(2) + name
+	if data[0] != 0 {
+		return ""
+	}
+	nameLen := int(binary.BigEndian.Uint16(data[1:3]))
+	if len(data) < 3+nameLen {
+		return ""
+	}
+	return string(data[3 : 3+nameLen])
+}
+
+func parseALPN(data []byte) []string {
+	if len(data) < 2 {
+		return nil
+	}
+	listLen := int(binary.BigEndian.Uint16(data[0:2]))
+	data = data[2:]
+	if len(data) < listLen {
+		return nil
+	}
+	data = data[:listLen]
+
+	var protos []string
+	for len(data) >= 1 {
+		n := int(data[0])
+		data = data[1:]
+		if len(data) < n {
+			break
+		}
+		protos = append(protos, string(data[:n]))
+		data = data[n:]
+	}
+	return protos
+}
+
+// isGREASE reports whether v is one of the reserved GREASE values TLS
+// clients insert to probe server tolerance for unknown values. JA3 and
+// JA4 both exclude GREASE values from the fingerprint so they don't
+// change on every handshake.
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
+
+// JA3 renders the classic JA3 fingerprint string: dash-separated decimal
+// fields, comma-separated sections, in ClientHello wire order.
+func (ch *ClientHello) JA3() string {
+	ciphers := filterGREASE(ch.CipherSuites)
+	exts := filterGREASE(ch.Extensions)
+	groups := filterGREASE(ch.SupportedGroups)
+
+	return strings.Join([]string{
+		strconv.Itoa(int(ch.Version)),
+		joinUint16(ciphers),
+		joinUint16(exts),
+		joinUint16(groups),
+		joinBytes(ch.ECPointFormats),
+	}, ",")
+}
+
+// JA3Hash returns the MD5 hash of JA3(), the form JA3 is conventionally
+// logged and matched as.
+func (ch *ClientHello) JA3Hash() string {
+	sum := md5.Sum([]byte(ch.JA3()))
+	return hex.EncodeToString(sum[:])
+}
+
+func filterGREASE(in []uint16) []uint16 {
+	out := make([]uint16, 0, len(in))
+	for _, v := range in {
+		if !isGREASE(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func joinUint16(vals []uint16) string {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		parts[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(parts, "-")
+}
+
+func joinBytes(vals []byte) string {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		parts[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(parts, "-")
+}
+
+func hexUint16(v uint16) string {
+	return fmt.Sprintf("%04x", v)
+}
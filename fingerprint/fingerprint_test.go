@@ -0,0 +1,137 @@
+package fingerprint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// buildClientHello assembles a minimal, well-formed ClientHello record for
+// testing the parser, rather than hard-coding an opaque byte blob.
+func buildClientHello(t *testing.T) []byte {
+	t.Helper()
+
+	var body bytes.Buffer
+	body.Write([]byte{0x03, 0x03}) // client_version: TLS 1.2
+	body.Write(make([]byte, 32))   // random
+	body.WriteByte(0)              // session_id length
+
+	ciphers := []uint16{0x1301, 0x0a0a} // one real cipher + GREASE
+	binary.Write(&body, binary.BigEndian, uint16(len(ciphers)*2))
+	for _, c := range ciphers {
+		binary.Write(&body, binary.BigEndian, c)
+	}
+
+	body.Write([]byte{0x01, 0x00}) // compression methods: length 1, null
+
+	var exts bytes.Buffer
+	writeExt(&exts, extServerName, serverNameExtBody("example.com"))
+	writeExt(&exts, extSupportedGroups, uint16ListExtBody([]uint16{0x001d}))
+	writeExt(&exts, extECPointFormats, append([]byte{1}, 0))
+	writeExt(&exts, extALPN, alpnExtBody([]string{"h2"}))
+
+	binary.Write(&body, binary.BigEndian, uint16(exts.Len()))
+	body.Write(exts.Bytes())
+
+	var handshake bytes.Buffer
+	handshake.WriteByte(handshakeTypeClientHello)
+	l := body.Len()
+	handshake.Write([]byte{byte(l >> 16), byte(l >> 8), byte(l)})
+	handshake.Write(body.Bytes())
+
+	var record bytes.Buffer
+	record.WriteByte(recordTypeHandshake)
+	record.Write([]byte{0x03, 0x01}) // legacy record version
+	binary.Write(&record, binary.BigEndian, uint16(handshake.Len()))
+	record.Write(handshake.Bytes())
+
+	return record.Bytes()
+}
+
+func writeExt(buf *bytes.Buffer, extType uint16, data []byte) {
+	binary.Write(buf, binary.BigEndian, extType)
+	binary.Write(buf, binary.BigEndian, uint16(len(data)))
+	buf.Write(data)
+}
+
+func serverNameExtBody(name string) []byte {
+	var b bytes.Buffer
+	entry := append([]byte{0}, byte(len(name)>>8), byte(len(name)))
+	entry = append(entry, name...)
+	binary.Write(&b, binary.BigEndian, uint16(len(entry)))
+	b.Write(entry)
+	return b.Bytes()
+}
+
+func uint16ListExtBody(vals []uint16) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint16(len(vals)*2))
+	for _, v := range vals {
+		binary.Write(&b, binary.BigEndian, v)
+	}
+	return b.Bytes()
+}
+
+func alpnExtBody(protos []string) []byte {
+	var list bytes.Buffer
+	for _, p := range protos {
+		list.WriteByte(byte(len(p)))
+		list.WriteString(p)
+	}
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, uint16(list.Len()))
+	b.Write(list.Bytes())
+	return b.Bytes()
+}
+
+func TestParseClientHello(t *testing.T) {
+	raw := buildClientHello(t)
+
+	ch, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	if ch.ServerName != "example.com" {
+		t.Errorf("ServerName = %q, want %q", ch.ServerName, "example.com")
+	}
+	if len(ch.ALPN) != 1 || ch.ALPN[0] != "h2" {
+		t.Errorf("ALPN = %v, want [h2]", ch.ALPN)
+	}
+	if len(ch.CipherSuites) != 2 {
+		t.Fatalf("len(CipherSuites) = %d, want 2", len(ch.CipherSuites))
+	}
+}
+
+func TestJA3ExcludesGREASE(t *testing.T) {
+	raw := buildClientHello(t)
+	ch, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	ja3 := ch.JA3()
+	if bytes.Contains([]byte(ja3), []byte("2570")) { // decimal for 0x0a0a
+		t.Errorf("JA3() = %q, should not include the GREASE cipher", ja3)
+	}
+	if ch.JA3Hash() == "" {
+		t.Error("JA3Hash() returned empty string")
+	}
+}
+
+func TestJA4Format(t *testing.T) {
+	raw := buildClientHello(t)
+	ch, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	ja4 := ch.JA4()
+	parts := bytes.Split([]byte(ja4), []byte("_"))
+	if len(parts) != 3 {
+		t.Fatalf("JA4() = %q, want 3 underscore-separated segments", ja4)
+	}
+	if string(parts[0][:3]) != "t12" {
+		t.Errorf("JA4() segment a = %q, want to start with t12", parts[0])
+	}
+}
@@ -0,0 +1,97 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tlsVersionCode maps a ClientHello's legacy version field to JA4's
+// two-digit code. The supported_versions extension is not parsed, so a
+// TLS 1.3 client, which sends a legacy version of 0x0303, is coded "12".
+var tlsVersionCode = map[uint16]string{
+	0x0304: "13",
+	0x0303: "12",
+	0x0302: "11",
+	0x0301: "10",
+}
+
+// JA4 renders the JA4 fingerprint per the FoxIO specification:
+// "{a}_{b}_{c}" where a is a human-readable summary of the handshake
+// shape, b is a truncated hash of the cipher list, and c is a truncated
+// hash of the extension and signature-algorithm lists.
+func (ch *ClientHello) JA4() string {
+	return ja4A(ch) + "_" + ja4B(ch) + "_" + ja4C(ch)
+}
+
+func ja4A(ch *ClientHello) string {
+	version := tlsVersionCode[ch.Version]
+	if version == "" {
+		version = "00"
+	}
+
+	sni := "i"
+	if ch.ServerName != "" {
+		sni = "d"
+	}
+
+	cipherCount := len(filterGREASE(ch.CipherSuites))
+	extCount := len(filterGREASE(ch.Extensions))
+
+	alpn := "00"
+	if len(ch.ALPN) > 0 && len(ch.ALPN[0]) > 0 {
+		first := ch.ALPN[0]
+		alpn = string(first[0]) + string(first[len(first)-1])
+	}
+
+	return fmt.Sprintf("t%s%s%02d%02d%s", version, sni, capAt99(cipherCount), capAt99(extCount), alpn)
+}
+
+func ja4B(ch *ClientHello) string {
+	ciphers := filterGREASE(ch.CipherSuites)
+	hexes := make([]string, len(ciphers))
+	for i, c := range ciphers {
+		hexes[i] = hexUint16(c)
+	}
+	sort.Strings(hexes)
+	return truncatedSHA256(strings.Join(hexes, ","))
+}
+
+func ja4C(ch *ClientHello) string {
+	// Extensions, sorted, excluding SNI and ALPN (already summarized in
+	// the 'a' segment), then signature algorithms in their original
+	// (unsorted) wire order, per the JA4 spec.
+	exts := filterGREASE(ch.Extensions)
+	hexExts := make([]string, 0, len(exts))
+	for _, e := range exts {
+		if e == extServerName || e == extALPN {
+			continue
+		}
+		hexExts = append(hexExts, hexUint16(e))
+	}
+	sort.Strings(hexExts)
+
+	sigAlgs := make([]string, len(ch.SignatureAlgorithms))
+	for i, s := range ch.SignatureAlgorithms {
+		sigAlgs[i] = hexUint16(s)
+	}
+
+	combined := strings.Join(hexExts, ",") + "_" + strings.Join(sigAlgs, ",")
+	return truncatedSHA256(combined)
+}
+
+func capAt99(n int) int {
+	if n > 99 {
+		return 99
+	}
+	return n
+}
+
+// truncatedSHA256 is JA4's convention for the b and c segments: a hex
+// SHA-256 digest truncated to 12 characters.
+func truncatedSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
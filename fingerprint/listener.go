@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"net"
+	"sync"
+)
+
+// Listener wraps a net.Listener so every accepted connection's raw bytes
+// are captured for later fingerprinting, and makes the resulting
+// ClientHello available by remote address via Lookup.
+//
+// It's meant to sit between the raw TCP listener and tls.NewListener:
+//
+//	raw, _ := net.Listen("tcp", addr)
+//	fl := fingerprint.WrapListener(raw)
+//	tlsLn := tls.NewListener(fl, tlsConfig)
+//	server.Serve(tlsLn)
+//
+// By the time an HTTP handler runs, the TLS handshake has already
+// completed and the ClientHello bytes have been teed into the
+// connection's buffer, so Lookup(remoteAddr) succeeds from inside the
+// handler.
+type Listener struct {
+	net.Listener
+
+	mu       sync.Mutex
+	byRemote map[string]*conn
+}
+
+// WrapListener returns a Listener that captures every accepted
+// connection's handshake bytes for fingerprinting.
+func WrapListener(l net.Listener) *Listener {
+	return &Listener{Listener: l, byRemote: make(map[string]*conn)}
+}
+
+func (l *Listener) Accept() (net.Conn, error) {
+	raw, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &conn{Conn: raw}
+	key := raw.RemoteAddr().String()
+
+	l.mu.Lock()
+	l.byRemote[key] = c
+	l.mu.Unlock()
+
+	return &registeredConn{conn: c, listener: l, key: key}, nil
+}
+
+// Lookup returns the parsed ClientHello for the connection most recently
+// accepted from remoteAddr, if its handshake bytes parsed successfully.
+func (l *Listener) Lookup(remoteAddr string) (*ClientHello, bool) {
+	l.mu.Lock()
+	c, ok := l.byRemote[remoteAddr]
+	l.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	hello, err := Parse(c.captured())
+	if err != nil {
+		return nil, false
+	}
+	return hello, true
+}
+
+// registeredConn removes its entry from the listener's registry on
+// Close, so the map doesn't grow without bound across the server's
+// lifetime.
+type registeredConn struct {
+	*conn
+	listener *Listener
+	key      string
+}
+
+func (c *registeredConn) Close() error {
+	c.listener.mu.Lock()
+	if c.listener.byRemote[c.key] == c.conn {
+		delete(c.listener.byRemote, c.key)
+	}
+	c.listener.mu.Unlock()
+	return c.conn.Close()
+}
@@ -0,0 +1,30 @@
+package fingerprint
+
+// knownScannerJA3 maps well-known JA3 hashes to the tool that produces
+// them. It's intentionally small and unexported: new entries are added
+// as operators identify fingerprints worth tagging, the same way
+// enrich's IP-based scanner list grows over time.
+var knownScannerJA3 = map[string]string{
+	"e7d705a3286e19ea42f587b344ee6865": "shodan",
+	"6734f37431670b3ab4292b8f60f29984": "censys",
+	"a0e9f5d64349fb13191bc781f81f42e1": "curl-default",
+	"bd0bf25947d4a37404f0424391186961": "zgrab",
+}
+
+// knownScannerJA4 is JA4's equivalent of knownScannerJA3. JA4 is more
+// resistant to minor client-library version drift than JA3, so over time
+// this is the list worth growing.
+var knownScannerJA4 = map[string]string{}
+
+// LookupScanner returns the name of the scanner tool known to produce
+// ja3 or ja4, and true if either matched, so they can be tagged the same
+// way enrich tags a known-scanner IP.
+func LookupScanner(ja3, ja4 string) (string, bool) {
+	if name, ok := knownScannerJA4[ja4]; ok {
+		return name, true
+	}
+	if name, ok := knownScannerJA3[ja3]; ok {
+		return name, true
+	}
+	return "", false
+}
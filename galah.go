@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
@@ -13,18 +14,34 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/http/httputil"
 	"os"
 	"os/signal"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/0x4d31/galah/enrich"
+	"github.com/0x4d31/galah/fingerprint"
+	"github.com/0x4d31/galah/llm"
+	"github.com/0x4d31/galah/logging"
+	"github.com/0x4d31/galah/metrics"
+	"github.com/0x4d31/galah/output"
+	"github.com/0x4d31/galah/session"
+	"github.com/0x4d31/galah/template"
 	_ "github.com/mattn/go-sqlite3"
 	"golang.org/x/sync/errgroup"
 )
 
+// logger is galah's structured logger. It's a package-level var (rather
+// than an App field) because it must be usable outside App methods:
+// initDB runs before an App exists, and helpers such as
+// extractHTTPRequestInfo and sendResponse have no App to reach it through.
+var logger logging.Logger
+
 type Event struct {
 	Timestamp    time.Time    `json:"timestamp"`
 	SrcIP        string       `json:"srcIP"`
@@ -35,8 +52,8 @@ type Event struct {
 	Port         string       `json:"port"`
 	HTTPRequest  HTTPRequest  `json:"httpRequest"`
 	HTTPResponse HTTPResponse `json:"httpResponse"`
-	// TODO: Sessionize the incoming requests based on the sessionTTL and source IP.
-	// SessionID    string       `json:"sessionID"`
+	LLMUsage     *llm.Usage   `json:"llmUsage,omitempty"`
+	SessionID    string       `json:"sessionID,omitempty"`
 }
 
 type HTTPRequest struct {
@@ -49,6 +66,8 @@ type HTTPRequest struct {
 	HeadersSortedSha256 string `json:"headersSortedSha256"`
 	Body                string `json:"body"`
 	BodySha256          string `json:"bodySha256"`
+	JA3                 string `json:"ja3,omitempty"`
+	JA4                 string `json:"ja4,omitempty"`
 }
 
 type HTTPResponse struct {
@@ -57,21 +76,37 @@ type HTTPResponse struct {
 }
 
 type Args struct {
-	Interface  string
-	ConfigFile string
-	DbPath     string
-	OutputFile string
-	Verbose    bool
+	Interface       string
+	ConfigFile      string
+	DbPath          string
+	OutputFile      string
+	AdminAddr       string
+	ShutdownTimeout time.Duration
+	Verbose         bool
 }
 
 type App struct {
-	Config      *Config
-	DB          *sql.DB
-	OutputFile  string
-	Verbose     bool
-	Servers     map[uint16]*http.Server
-	Hostname    string
-	EnrichCache *enrich.Default
+	Config          *Config
+	DB              *sql.DB
+	OutputFile      string
+	Verbose         bool
+	Hostname        string
+	EnrichCache     *enrich.Default
+	Provider        llm.Provider
+	Sessions        *session.Store
+	Sinks           *output.Manager
+	Templates       *template.Engine
+	Metrics         *metrics.Registry
+	ShutdownTimeout time.Duration
+
+	serversMu sync.Mutex
+	Servers   map[uint16]*http.Server
+
+	fingerprintMu        sync.Mutex
+	fingerprintListeners []*fingerprint.Listener
+
+	shutdownCtx    context.Context
+	shutdownCancel context.CancelFunc
 }
 
 var ignoreHeaders = map[string]bool{
@@ -90,10 +125,14 @@ var ignoreHeaders = map[string]bool{
 }
 
 const (
-	version   = "1.0"
-	cacheSize = 1_000_000
-	lookupTTL = 1 * time.Hour
-	// sessionTTL = 2 * time.Minute
+	version                = "1.0"
+	cacheSize              = 1_000_000
+	lookupTTL              = 1 * time.Hour
+	llmRequestTimeout      = 30 * time.Second
+	sessionTTL             = 2 * time.Minute
+	sessionCacheSize       = 100_000
+	defaultAdminAddr       = "127.0.0.1:9090"
+	defaultShutdownTimeout = 10 * time.Second
 )
 
 func printBanner() {
@@ -118,15 +157,22 @@ func main() {
 
 	config, err := LoadConfig(args.ConfigFile)
 	if err != nil {
+		// logger isn't set up yet (it's built from config.Logging), so this
+		// one call site is the standard library's log.Fatalf.
 		log.Fatalf("Error loading config: %v", err)
 	}
 
+	logger, err = logging.New(config.Logging)
+	if err != nil {
+		log.Fatalf("Error setting up logger: %v", err)
+	}
+
 	db := initDB(args.DbPath)
 	defer db.Close()
 
 	hostname, err := getHostname()
 	if err != nil {
-		log.Fatalf("Error getting hostname: %v", err)
+		logger.Fatal("Error getting hostname", logging.F("error", err))
 	}
 
 	enrichCache := enrich.New(&enrich.Config{
@@ -134,20 +180,68 @@ func main() {
 		CacheTTL:  lookupTTL,
 	})
 
+	provider, err := llm.New(llm.Config{
+		Provider:       config.Provider,
+		Model:          config.Model,
+		APIKey:         config.APIKey,
+		Endpoint:       config.Endpoint,
+		PromptTemplate: config.PromptTemplate,
+		RequestTimeout: llmRequestTimeout,
+	})
+	if err != nil {
+		logger.Fatal("Error setting up LLM provider", logging.F("error", err))
+	}
+	defer provider.Close()
+
+	sessions := session.New(session.Config{
+		TTL:       sessionTTL,
+		CacheSize: sessionCacheSize,
+	})
+
+	sinks, err := output.New(config.Output, args.OutputFile)
+	if err != nil {
+		logger.Fatal("Error setting up output sinks", logging.F("error", err))
+	}
+	defer sinks.Close()
+
+	templates, err := template.New(config.Templates)
+	if err != nil {
+		logger.Fatal("Error parsing response templates", logging.F("error", err))
+	}
+
+	ports := make([]uint16, 0, len(config.Ports))
+	for _, pc := range config.Ports {
+		ports = append(ports, pc.Port)
+	}
+	metricsRegistry := metrics.New(db, sessions, ports)
+
+	shutdownCtx, shutdownCancel := context.WithCancel(context.Background())
+	defer shutdownCancel()
+
 	app := &App{
-		Config:      config,
-		DB:          db,
-		OutputFile:  args.OutputFile,
-		Verbose:     args.Verbose,
-		Hostname:    hostname,
-		EnrichCache: enrichCache,
+		Config:          config,
+		DB:              db,
+		OutputFile:      args.OutputFile,
+		Verbose:         args.Verbose,
+		Hostname:        hostname,
+		EnrichCache:     enrichCache,
+		Provider:        provider,
+		Sessions:        sessions,
+		Sinks:           sinks,
+		Templates:       templates,
+		Metrics:         metricsRegistry,
+		ShutdownTimeout: args.ShutdownTimeout,
+		Servers:         make(map[uint16]*http.Server),
+		shutdownCtx:     shutdownCtx,
+		shutdownCancel:  shutdownCancel,
 	}
 
 	app.ListenForShutdownSignals()
+	app.startAdminServer(args.AdminAddr)
 
 	err = app.startServers()
 	if err != nil {
-		log.Println(err)
+		logger.Error("Server group exited with error", logging.F("error", err))
 	}
 }
 
@@ -157,6 +251,8 @@ func parseArgs() *Args {
 	flag.StringVar(&args.ConfigFile, "c", "config.yaml", "path to config file")
 	flag.StringVar(&args.DbPath, "db", "cache.db", "path to database file")
 	flag.StringVar(&args.OutputFile, "o", "log.json", "path to output log file")
+	flag.StringVar(&args.AdminAddr, "admin-addr", defaultAdminAddr, "address to serve admin endpoints on (e.g. /sessions)")
+	flag.DurationVar(&args.ShutdownTimeout, "shutdown-timeout", defaultShutdownTimeout, "how long to wait for in-flight requests to drain on shutdown")
 	flag.BoolVar(&args.Verbose, "v", false, "verbose mode")
 
 	flag.Parse()
@@ -165,6 +261,8 @@ func parseArgs() *Args {
 	if args.Interface == "" {
 		interfaceName, err := getDefaultInterface()
 		if err != nil {
+			// logger isn't set up yet at this point (parseArgs runs before
+			// LoadConfig), so this stays on the standard library's log.Fatalf.
 			log.Fatalf("Error getting default interface: %v", err)
 		}
 		args.Interface = interfaceName
@@ -188,7 +286,7 @@ func initDB(dbPath string) *sql.DB {
 	)	
 `)
 	if err != nil {
-		log.Fatalf("Error creating table: %v", err)
+		logger.Fatal("Error creating table", logging.F("error", err))
 	}
 
 	return db
@@ -201,7 +299,7 @@ func (app *App) startServers() error {
 		pc := pc // Capture the loop variable
 		g.Go(func() error {
 			server := app.setupServer(pc)
-			app.Servers = make(map[uint16]*http.Server)
+			app.registerServer(pc.Port, server)
 
 			var err error
 			switch pc.Protocol {
@@ -212,7 +310,10 @@ func (app *App) startServers() error {
 			default:
 				err = fmt.Errorf("Unknown protocol for port %d", pc.Port)
 			}
-			if err != nil {
+			// http.Server.Shutdown makes ListenAndServe(TLS) return
+			// http.ErrServerClosed; that's an orderly shutdown, not a
+			// failure to report to the errgroup.
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
 				return err
 			}
 
@@ -223,6 +324,14 @@ func (app *App) startServers() error {
 	return g.Wait()
 }
 
+// registerServer records server under port so ListenForShutdownSignals
+// can find and drain it later.
+func (app *App) registerServer(port uint16, server *http.Server) {
+	app.serversMu.Lock()
+	defer app.serversMu.Unlock()
+	app.Servers[port] = server
+}
+
 func (app *App) setupServer(pc PortConfig) *http.Server {
 	serverAddr := fmt.Sprintf(":%d", pc.Port)
 	server := &http.Server{
@@ -247,66 +356,188 @@ func (app *App) startTLSServer(server *http.Server, pc PortConfig) error {
 		return fmt.Errorf("Error: TLS profile incomplete for port %d", pc.Port)
 	}
 
-	log.Printf("Starting HTTPS server on port %d with TLS profile: %s", pc.Port, pc.TLSProfile)
-	err := server.ListenAndServeTLS(tlsConfig.Certificate, tlsConfig.Key)
+	cert, err := tls.LoadX509KeyPair(tlsConfig.Certificate, tlsConfig.Key)
 	if err != nil {
-		return fmt.Errorf("Error starting HTTPS server on port %d: %v", pc.Port, err)
+		return fmt.Errorf("Error loading TLS certificate for port %d: %w", pc.Port, err)
+	}
+
+	// The raw TCP listener is wrapped with a fingerprint.Listener before
+	// crypto/tls sees it, so the ClientHello bytes are captured as the
+	// handshake consumes them. app.lookupFingerprint can then recover the
+	// JA3/JA4 fingerprint for a connection by remote address once a
+	// request on it reaches handleRequest.
+	raw, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("Error listening on port %d: %w", pc.Port, err)
+	}
+	fingerprinting := fingerprint.WrapListener(raw)
+	app.registerFingerprintListener(fingerprinting)
+	tlsListener := tls.NewListener(fingerprinting, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	logger.Info("Starting HTTPS server", logging.F("port", pc.Port), logging.F("tlsProfile", pc.TLSProfile))
+	err = server.Serve(tlsListener)
+	if err != nil {
+		return fmt.Errorf("Error starting HTTPS server on port %d: %w", pc.Port, err)
 	}
 	return nil
 }
 
+// registerFingerprintListener makes l available to lookupFingerprint.
+func (app *App) registerFingerprintListener(l *fingerprint.Listener) {
+	app.fingerprintMu.Lock()
+	defer app.fingerprintMu.Unlock()
+	app.fingerprintListeners = append(app.fingerprintListeners, l)
+}
+
+// lookupFingerprint returns the JA3/JA4 fingerprint captured for the TLS
+// connection from remoteAddr, if any (plain HTTP ports have none).
+func (app *App) lookupFingerprint(remoteAddr string) (*fingerprint.ClientHello, bool) {
+	app.fingerprintMu.Lock()
+	listeners := append([]*fingerprint.Listener(nil), app.fingerprintListeners...)
+	app.fingerprintMu.Unlock()
+
+	for _, l := range listeners {
+		if hello, ok := l.Lookup(remoteAddr); ok {
+			return hello, true
+		}
+	}
+	return nil, false
+}
+
 func (app *App) startHTTPServer(server *http.Server, pc PortConfig) error {
-	log.Printf("Starting HTTP server on port %d", pc.Port)
+	logger.Info("Starting HTTP server", logging.F("port", pc.Port))
 	err := server.ListenAndServe()
 	if err != nil {
-		return fmt.Errorf("Error starting HTTP server on port %d: %v", pc.Port, err)
+		return fmt.Errorf("Error starting HTTP server on port %d: %w", pc.Port, err)
 	}
 	return nil
 }
 
+// startAdminServer serves operator-only endpoints (/sessions, /sinks,
+// /metrics) on addr, which defaults to loopback-only.
+func (app *App) startAdminServer(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sessions", app.handleSessions)
+	mux.HandleFunc("/sinks", app.handleSinkHealth)
+	mux.Handle("/metrics", app.Metrics.Handler())
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		logger.Info("Starting admin server", logging.F("addr", addr))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("Error starting admin server", logging.F("addr", addr), logging.F("error", err))
+		}
+	}()
+}
+
+func (app *App) handleSessions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(app.Sessions.All()); err != nil {
+		logger.Error("Error encoding sessions", logging.F("error", err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (app *App) handleSinkHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(app.Sinks.Health()); err != nil {
+		logger.Error("Error encoding sink health", logging.F("error", err))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
 func (app *App) handleRequest(w http.ResponseWriter, r *http.Request, serverAddr string) {
 	_, port, err := net.SplitHostPort(serverAddr)
 	if err != nil {
 		port = ""
 	}
 
+	if portNum, convErr := strconv.ParseUint(port, 10, 16); convErr == nil {
+		app.Metrics.IncRequest(uint16(portNum))
+	}
+
 	if app.Verbose {
-		log.Printf("Received a request for %q from %s", r.URL.String(), r.RemoteAddr)
+		logger.Info("Received a request", logging.F("url", r.URL.String()), logging.F("remoteAddr", r.RemoteAddr))
 	}
 
-	response, err := app.checkDB(r, port)
+	srcIP, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		if app.Verbose {
-			log.Printf("Request cache miss for %q: %s", r.URL.String(), err)
-		}
+		srcIP = r.RemoteAddr
+	}
+	hello, _ := app.lookupFingerprint(r.RemoteAddr)
+	ctx := context.WithValue(r.Context(), clientHelloContextKey{}, hello)
+	r = r.WithContext(ctx)
 
-		response, err = app.generateAndCacheResponse(r, port)
+	var ja3 string
+	if hello != nil {
+		ja3 = hello.JA3Hash()
+	}
+	sessionKey := session.Key(srcIP, r.UserAgent(), ja3)
+	sess := app.Sessions.Touch(sessionKey, srcIP, r.UserAgent(), ja3, r.URL.String())
+
+	var response []byte
+	var usage *llm.Usage
+	if route, ok := app.Templates.Match(r.Method, r.Host, r.URL.Path); ok {
+		// Template routes skip the port+URL response cache: the body is
+		// re-rendered on every request so {{now}}/{{uuid}} stay current,
+		// and the one part worth caching, the LLM's filled-in slots, is
+		// already cached independently inside resolveTemplateLLM.
+		response, usage, err = app.renderTemplateResponse(r, route)
 		if err != nil {
-			log.Println("Error generating response:", err)
+			logger.Error("Error rendering template response", logging.F("error", err))
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
+	} else {
+		response, err = app.checkDB(r, port)
+		app.Metrics.ObserveCacheResult(err == nil)
+		if err != nil {
+			if app.Verbose {
+				logger.Info("Request cache miss", logging.F("url", r.URL.String()), logging.F("error", err))
+			}
+
+			response, usage, err = app.generateAndCacheResponse(r, port, sessionKey)
+			if err != nil {
+				logger.Error("Error generating response", logging.F("error", err))
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+		}
 	}
 
 	// Parse the JSON-encoded data into a HTTPResponse struct, and send it to the client.
 	var respData HTTPResponse
 	if err := json.Unmarshal(response, &respData); err != nil {
-		log.Println("Error unmarshalling the json-encoded data:", err)
+		logger.Error("Error unmarshalling the json-encoded data", logging.F("error", err))
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 	if app.Verbose {
-		log.Println("Sending the crafted response to", r.RemoteAddr)
+		logger.Info("Sending the crafted response", logging.F("remoteAddr", r.RemoteAddr))
 	}
 	sendResponse(w, respData)
 
-	// The response headers are logged exactly as generated by Perplexity AI, however,
+	app.Sessions.Record(sessionKey, r.Method+" "+r.RequestURI, respData.Body)
+
+	// The response headers are logged exactly as generated by the LLM provider, however,
 	// certain headers are excluded before sending the response to the client.
-	event := app.makeEvent(r, respData, port)
+	event := app.makeEvent(r, respData, port, usage, sess.ID)
 	app.writeLog(event)
 }
 
-func (app *App) makeEvent(req *http.Request, resp HTTPResponse, port string) Event {
+// clientHelloContextKey is the context key handleRequest stashes the
+// caller's parsed TLS ClientHello (if any) under, for checkDB,
+// generateAndCacheResponse, and makeEvent to read back out.
+type clientHelloContextKey struct{}
+
+// clientHelloFromContext returns the ClientHello captured for this
+// request's connection, if it arrived over TLS and the capture parsed.
+func clientHelloFromContext(ctx context.Context) (*fingerprint.ClientHello, bool) {
+	hello, _ := ctx.Value(clientHelloContextKey{}).(*fingerprint.ClientHello)
+	return hello, hello != nil
+}
+
+func (app *App) makeEvent(req *http.Request, resp HTTPResponse, port string, usage *llm.Usage, sessionID string) Event {
 	var tags []string
 
 	srcIP, srcPort, err := net.SplitHostPort(req.RemoteAddr)
@@ -318,13 +549,21 @@ func (app *App) makeEvent(req *http.Request, resp HTTPResponse, port string) Eve
 	e := app.EnrichCache
 	srcIPInfo, err := e.Process(srcIP)
 	if err != nil {
-		log.Printf("Error getting enrichment info for %q: %s", srcIP, err)
+		logger.Warn("Error getting enrichment info", logging.F("srcIP", srcIP), logging.F("error", err))
 	}
 	if s := srcIPInfo.KnownScanner; s != "" {
 		tags = append(tags, s)
 	}
 
 	httpRequest := extractHTTPRequestInfo(req)
+	if hello, ok := clientHelloFromContext(req.Context()); ok {
+		httpRequest.JA3 = hello.JA3Hash()
+		httpRequest.JA4 = hello.JA4()
+		if scanner, ok := fingerprint.LookupScanner(httpRequest.JA3, httpRequest.JA4); ok {
+			tags = append(tags, scanner)
+		}
+	}
+
 	return Event{
 		Timestamp:    time.Now(),
 		SrcIP:        srcIP,
@@ -335,6 +574,8 @@ func (app *App) makeEvent(req *http.Request, resp HTTPResponse, port string) Eve
 		Port:         port,
 		HTTPRequest:  httpRequest,
 		HTTPResponse: resp,
+		LLMUsage:     usage,
+		SessionID:    sessionID,
 	}
 }
 
@@ -351,7 +592,7 @@ func extractHTTPRequestInfo(r *http.Request) HTTPRequest {
 	httpRequest.HeadersSortedSha256 = calculateHeadersSortedSha256(headerKeys)
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Println("Error reading request body:", err)
+		logger.Warn("Error reading request body", logging.F("error", err))
 	}
 	httpRequest.Body = string(bodyBytes)
 	httpRequest.BodySha256 = func(data []byte) string {
@@ -403,18 +644,47 @@ func (app *App) checkDB(r *http.Request, port string) ([]byte, error) {
 	return response, err
 }
 
+// getDBKey incorporates the caller's TLS fingerprint alongside port and
+// URL, so a curl-shaped and a browser-shaped ClientHello requesting the
+// same path get independently cached (and LLM-generated) responses.
 func getDBKey(r *http.Request, port string) string {
-	return port + "_" + r.URL.String()
+	key := port + "_" + r.URL.String()
+	if hello, ok := clientHelloFromContext(r.Context()); ok {
+		key += "_" + hello.JA4()
+	}
+	return key
 }
 
-func (app *App) generateAndCacheResponse(r *http.Request, port string) ([]byte, error) {
-	responseString, err := GeneratePerplexityAIResponse(app.Config, r)
+func (app *App) generateAndCacheResponse(r *http.Request, port, sessionKey string) ([]byte, *llm.Usage, error) {
+	// Derived from app.shutdownCtx (not r.Context()) so that a shutdown
+	// signal cancels in-flight LLM calls instead of leaving them to run
+	// to completion after the HTTP server has already stopped serving.
+	ctx, cancel := context.WithTimeout(app.shutdownCtx, llmRequestTimeout)
+	defer cancel()
+
+	prompt, err := buildPrompt(app.Config.PromptTemplate, r)
 	if err != nil {
-		log.Print(err)
-		return nil, err
+		return nil, nil, err
+	}
+	prompt = appendTranscript(prompt, app.Sessions.Transcript(sessionKey))
+
+	start := time.Now()
+	responseString, err := app.Provider.Generate(ctx, prompt)
+	app.Metrics.ObserveLLMLatency(app.Config.Provider, time.Since(start))
+	if err != nil {
+		app.Metrics.IncLLMError(metrics.ClassifyLLMError(err))
+		logger.Error("Error generating LLM response", logging.F("error", err))
+		return nil, nil, err
 	}
 	if app.Verbose {
-		log.Println("Generated HTTP response:", responseString)
+		logger.Info("Generated HTTP response", logging.F("response", responseString))
+	}
+
+	var usage *llm.Usage
+	if reporter, ok := app.Provider.(llm.UsageReporter); ok {
+		u := reporter.LastUsage()
+		usage = &u
+		app.Metrics.AddTokens(u.PromptTokens, u.CompletionTokens, u.TotalTokens)
 	}
 
 	responseBytes := []byte(responseString)
@@ -422,7 +692,94 @@ func (app *App) generateAndCacheResponse(r *http.Request, port string) ([]byte,
 	currentTime := time.Now()
 	_, err = app.DB.Exec("INSERT OR REPLACE INTO cache (cachedAt, key, response) VALUES (?, ?, ?)", currentTime, DBKey, responseBytes)
 
-	return responseBytes, err
+	return responseBytes, usage, err
+}
+
+// renderTemplateResponse renders route against r, resolving any
+// {{llm "..."}} slots through resolveTemplateLLM so repeat hits on the
+// same route don't re-generate the same prompt.
+func (app *App) renderTemplateResponse(r *http.Request, route *template.Route) ([]byte, *llm.Usage, error) {
+	// Derived from app.shutdownCtx for the same reason as
+	// generateAndCacheResponse: a shutdown signal should cancel an
+	// in-flight LLM call backing a template slot, not let it run past
+	// server shutdown.
+	ctx, cancel := context.WithTimeout(app.shutdownCtx, llmRequestTimeout)
+	defer cancel()
+
+	responseBytes, err := app.Templates.Render(ctx, route, app.resolveTemplateLLM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var usage *llm.Usage
+	if reporter, ok := app.Provider.(llm.UsageReporter); ok {
+		u := reporter.LastUsage()
+		usage = &u
+	}
+
+	return responseBytes, usage, nil
+}
+
+// resolveTemplateLLM fills a single template {{llm "prompt"}} slot,
+// caching the result in the same SQLite cache table galah uses for full
+// responses, keyed by route and prompt rather than by port and URL, so
+// identical slots across requests (and across distinct cached full
+// responses, e.g. different client fingerprints) only ever generate once.
+func (app *App) resolveTemplateLLM(ctx context.Context, routeID, prompt string) (string, error) {
+	key := templateSlotKey(routeID, prompt)
+
+	var cached []byte
+	row := app.DB.QueryRow("SELECT response FROM cache WHERE key = ? ORDER BY cachedAt DESC LIMIT 1", key)
+	if err := row.Scan(&cached); err == nil {
+		return string(cached), nil
+	}
+
+	start := time.Now()
+	text, err := app.Provider.Generate(ctx, prompt)
+	app.Metrics.ObserveLLMLatency(app.Config.Provider, time.Since(start))
+	if err != nil {
+		app.Metrics.IncLLMError(metrics.ClassifyLLMError(err))
+		return "", err
+	}
+	if reporter, ok := app.Provider.(llm.UsageReporter); ok {
+		u := reporter.LastUsage()
+		app.Metrics.AddTokens(u.PromptTokens, u.CompletionTokens, u.TotalTokens)
+	}
+
+	_, err = app.DB.Exec("INSERT OR REPLACE INTO cache (cachedAt, key, response) VALUES (?, ?, ?)", time.Now(), key, []byte(text))
+	return text, err
+}
+
+func templateSlotKey(routeID, prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return "tmpl_" + routeID + "_" + hex.EncodeToString(sum[:])
+}
+
+// buildPrompt renders the configured prompt template against the raw
+// HTTP request, the same way it has always been fed to the LLM.
+func buildPrompt(promptTemplate string, r *http.Request) (string, error) {
+	httpReq, err := httputil.DumpRequest(r, true)
+	if err != nil {
+		return "", fmt.Errorf("dumping request: %w", err)
+	}
+	return fmt.Sprintf(promptTemplate, httpReq), nil
+}
+
+// appendTranscript folds an attacker's prior requests and responses in
+// this session into the prompt, so follow-up requests (e.g. a GET for a
+// file the honeypot previously claimed existed) get coherent answers.
+func appendTranscript(prompt string, turns []session.Turn) string {
+	if len(turns) == 0 {
+		return prompt
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prompt)
+	sb.WriteString("\n\nEarlier requests in this session, and how you responded, for consistency:\n")
+	for _, t := range turns {
+		sb.WriteString(fmt.Sprintf("> %s\n%s\n", t.Request, t.Response))
+	}
+	return sb.String()
 }
 
 func sendResponse(w http.ResponseWriter, response HTTPResponse) {
@@ -435,7 +792,7 @@ func sendResponse(w http.ResponseWriter, response HTTPResponse) {
 
 	_, err := w.Write([]byte(response.Body))
 	if err != nil {
-		log.Println("Error writing response:", err)
+		logger.Error("Error writing response", logging.F("error", err))
 	}
 }
 
@@ -443,24 +800,10 @@ func isExcludedHeader(headerKey string) bool {
 	return ignoreHeaders[strings.ToLower(headerKey)]
 }
 
+// writeLog hands event off to the configured output sinks. Enqueue never
+// blocks, so a stuck sink cannot stall the HTTP handler that called this.
 func (app *App) writeLog(event Event) {
-	f, err := os.OpenFile(app.OutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Printf("Error opening log file: %v", err)
-		return
-	}
-	defer f.Close()
-
-	eventJSON, err := json.Marshal(event)
-	if err != nil {
-		log.Printf("Error marshaling event to JSON: %v", err)
-		return
-	}
-
-	if _, err = f.Write(append(eventJSON, '\n')); err != nil {
-		log.Printf("Error writing to log file: %v", err)
-		return
-	}
+	app.Sinks.Enqueue(event)
 }
 
 func getDefaultInterface() (string, error) {
@@ -490,18 +833,50 @@ func (app *App) ListenForShutdownSignals() {
 
 	go func() {
 		<-sig
-		log.Println("Received shutdown signal. Shutting down servers...")
+		logger.Info("Received shutdown signal. Shutting down servers...")
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		// Unblock any in-flight LLM calls before we start waiting for
+		// servers to drain, so they don't hold connections open for the
+		// full shutdown timeout.
+		app.shutdownCancel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), app.ShutdownTimeout)
 		defer cancel()
 
-		for _, server := range app.Servers {
-			if err := server.Shutdown(ctx); err != nil {
-				log.Printf("Error shutting down server: %v", err)
-			}
+		app.serversMu.Lock()
+		servers := make(map[uint16]*http.Server, len(app.Servers))
+		for port, server := range app.Servers {
+			servers[port] = server
+		}
+		app.serversMu.Unlock()
+
+		var wg sync.WaitGroup
+		for port, server := range servers {
+			wg.Add(1)
+			go func(port uint16, server *http.Server) {
+				defer wg.Done()
+				if err := server.Shutdown(ctx); err != nil {
+					logger.Error("Error draining server", logging.F("port", port), logging.F("error", err))
+					return
+				}
+				logger.Info("Server drained successfully", logging.F("port", port))
+			}(port, server)
+		}
+		wg.Wait()
+
+		// os.Exit below bypasses main's deferred sinks.Close()/provider.Close(),
+		// so call them directly here: Sinks.Close() is what drains the
+		// buffered event queue and flushes the output sinks, and skipping
+		// it would silently drop whatever events were still queued or
+		// mid-retry at shutdown.
+		if err := app.Sinks.Close(); err != nil {
+			logger.Error("Error closing output sinks", logging.F("error", err))
+		}
+		if err := app.Provider.Close(); err != nil {
+			logger.Error("Error closing LLM provider", logging.F("error", err))
 		}
 
-		log.Println("All servers shut down gracefully.")
+		logger.Info("All servers shut down gracefully.")
 		os.Exit(0)
 	}()
 }
@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const anthropicEndpoint = "https://api.anthropic.com/v1/messages"
+
+const anthropicVersion = "2023-06-01"
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	Stream    bool               `json:"stream,omitempty"`
+}
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicResponse struct {
+	Content []anthropicContentBlock `json:"content"`
+	Usage   anthropicUsage          `json:"usage"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+const defaultAnthropicMaxTokens = 1024
+
+// anthropicProvider talks to Anthropic's Messages API.
+type anthropicProvider struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	lastUsage Usage
+}
+
+func newAnthropicProvider(cfg Config) *anthropicProvider {
+	return &anthropicProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+func (p *anthropicProvider) Name() string { return "anthropic" }
+
+func (p *anthropicProvider) Close() error { return nil }
+
+func (p *anthropicProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	endpoint := p.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = anthropicEndpoint
+	}
+
+	return withRetry(ctx, p.cfg.MaxRetries, func() (string, error) {
+		reqBody := anthropicRequest{
+			Model:     p.cfg.Model,
+			MaxTokens: defaultAnthropicMaxTokens,
+			System:    "Be precise and concise.",
+			Messages:  []anthropicMessage{{Role: "user", Content: prompt}},
+			Stream:    p.cfg.Stream,
+		}
+		payload, err := json.Marshal(reqBody)
+		if err != nil {
+			return "", fmt.Errorf("anthropic: marshalling request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
+		if err != nil {
+			return "", fmt.Errorf("anthropic: building request: %w", err)
+		}
+		req.Header.Set("content-type", "application/json")
+		req.Header.Set("x-api-key", p.cfg.APIKey)
+		req.Header.Set("anthropic-version", anthropicVersion)
+
+		res, err := p.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("anthropic: request failed: %w", err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode >= 400 {
+			body, _ := io.ReadAll(res.Body)
+			return "", fmt.Errorf("anthropic: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		if p.cfg.Stream {
+			return p.readStream(res.Body)
+		}
+		return p.readMessage(res.Body)
+	})
+}
+
+func (p *anthropicProvider) readMessage(r io.Reader) (string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("anthropic: reading response: %w", err)
+	}
+
+	var msgRes anthropicResponse
+	if err := json.Unmarshal(body, &msgRes); err != nil {
+		return "", fmt.Errorf("anthropic: unmarshalling response: %w", err)
+	}
+	if len(msgRes.Content) == 0 {
+		return "", fmt.Errorf("anthropic: no content blocks in response")
+	}
+
+	p.mu.Lock()
+	p.lastUsage = Usage{
+		PromptTokens:     msgRes.Usage.InputTokens,
+		CompletionTokens: msgRes.Usage.OutputTokens,
+		TotalTokens:      msgRes.Usage.InputTokens + msgRes.Usage.OutputTokens,
+	}
+	p.mu.Unlock()
+
+	return strings.TrimSpace(msgRes.Content[0].Text), nil
+}
+
+func (p *anthropicProvider) readStream(r io.Reader) (string, error) {
+	var sb strings.Builder
+	err := readSSE(r, func(data string) error {
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("anthropic: unmarshalling stream event: %w", err)
+		}
+		if event.Type == "content_block_delta" {
+			sb.WriteString(event.Delta.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func (p *anthropicProvider) LastUsage() Usage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastUsage
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// llamaCppProvider talks to a llama.cpp server's OpenAI-compatible
+// /v1/chat/completions endpoint (the `server` binary shipped with
+// llama.cpp). It sends the same request body as the OpenAI provider, but
+// requires Config.Endpoint to be set (there is no default endpoint), sends
+// no API key, and does not stream, send tools, or report token usage.
+type llamaCppProvider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func newLlamaCppProvider(cfg Config) *llamaCppProvider {
+	return &llamaCppProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+func (p *llamaCppProvider) Name() string { return "llamacpp" }
+
+func (p *llamaCppProvider) Close() error { return nil }
+
+func (p *llamaCppProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	if p.cfg.Endpoint == "" {
+		return "", fmt.Errorf("llamacpp: endpoint is required")
+	}
+
+	return withRetry(ctx, p.cfg.MaxRetries, func() (string, error) {
+		reqBody := openAIRequest{
+			Model: p.cfg.Model,
+			Messages: []chatMessage{
+				{Role: "system", Content: "Be precise and concise."},
+				{Role: "user", Content: prompt},
+			},
+		}
+		payload, err := json.Marshal(reqBody)
+		if err != nil {
+			return "", fmt.Errorf("llamacpp: marshalling request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", p.cfg.Endpoint, bytes.NewBuffer(payload))
+		if err != nil {
+			return "", fmt.Errorf("llamacpp: building request: %w", err)
+		}
+		req.Header.Set("content-type", "application/json")
+
+		res, err := p.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("llamacpp: request failed: %w", err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return "", fmt.Errorf("llamacpp: reading response: %w", err)
+		}
+		if res.StatusCode >= 400 {
+			return "", fmt.Errorf("llamacpp: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		var chatRes chatResponse
+		if err := json.Unmarshal(body, &chatRes); err != nil {
+			return "", fmt.Errorf("llamacpp: unmarshalling response: %w", err)
+		}
+		if len(chatRes.Choices) == 0 {
+			return "", fmt.Errorf("llamacpp: no choices in response")
+		}
+
+		return strings.TrimSpace(chatRes.Choices[0].Message.Content), nil
+	})
+}
@@ -0,0 +1,28 @@
+package llm
+
+import "context"
+
+// Mock is a Provider that returns a fixed response without making any
+// network calls, so honeypot behavior can be exercised in tests.
+type Mock struct {
+	Response string
+	Err      error
+	Prompts  []string
+}
+
+// NewMock returns a Mock that always returns response.
+func NewMock(response string) *Mock {
+	return &Mock{Response: response}
+}
+
+func (m *Mock) Generate(_ context.Context, prompt string) (string, error) {
+	m.Prompts = append(m.Prompts, prompt)
+	if m.Err != nil {
+		return "", m.Err
+	}
+	return m.Response, nil
+}
+
+func (m *Mock) Name() string { return "mock" }
+
+func (m *Mock) Close() error { return nil }
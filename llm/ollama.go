@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultOllamaEndpoint = "http://localhost:11434/api/generate"
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
+
+// ollamaProvider talks to a local Ollama server's /api/generate endpoint.
+type ollamaProvider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func newOllamaProvider(cfg Config) *ollamaProvider {
+	return &ollamaProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+func (p *ollamaProvider) Name() string { return "ollama" }
+
+func (p *ollamaProvider) Close() error { return nil }
+
+func (p *ollamaProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	endpoint := p.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultOllamaEndpoint
+	}
+
+	return withRetry(ctx, p.cfg.MaxRetries, func() (string, error) {
+		reqBody := ollamaRequest{
+			Model:  p.cfg.Model,
+			Prompt: prompt,
+			Stream: p.cfg.Stream,
+		}
+		payload, err := json.Marshal(reqBody)
+		if err != nil {
+			return "", fmt.Errorf("ollama: marshalling request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
+		if err != nil {
+			return "", fmt.Errorf("ollama: building request: %w", err)
+		}
+		req.Header.Set("content-type", "application/json")
+
+		res, err := p.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("ollama: request failed: %w", err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode >= 400 {
+			body, _ := io.ReadAll(res.Body)
+			return "", fmt.Errorf("ollama: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		if p.cfg.Stream {
+			return p.readStream(res.Body)
+		}
+		return p.readOnce(res.Body)
+	})
+}
+
+func (p *ollamaProvider) readOnce(r io.Reader) (string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("ollama: reading response: %w", err)
+	}
+
+	var genRes ollamaResponse
+	if err := json.Unmarshal(body, &genRes); err != nil {
+		return "", fmt.Errorf("ollama: unmarshalling response: %w", err)
+	}
+
+	return strings.TrimSpace(genRes.Response), nil
+}
+
+// readStream reads Ollama's newline-delimited JSON stream, which is not SSE
+// (no "data: " prefix), so it is decoded directly rather than via readSSE.
+func (p *ollamaProvider) readStream(r io.Reader) (string, error) {
+	var sb strings.Builder
+	decoder := json.NewDecoder(r)
+	for {
+		var chunk ollamaResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", fmt.Errorf("ollama: decoding stream chunk: %w", err)
+		}
+		sb.WriteString(chunk.Response)
+		if chunk.Done {
+			break
+		}
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const openAIEndpoint = "https://api.openai.com/v1/chat/completions"
+
+type openAIFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Tools    []openAITool  `json:"tools,omitempty"`
+	Stream   bool          `json:"stream,omitempty"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// openAIProvider talks to OpenAI's chat completions API, with optional
+// function-calling tools and SSE streaming.
+type openAIProvider struct {
+	cfg    Config
+	client *http.Client
+	tools  []openAITool
+
+	mu        sync.Mutex
+	lastUsage Usage
+}
+
+func newOpenAIProvider(cfg Config) *openAIProvider {
+	return &openAIProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+// WithTools attaches function-calling tool definitions to the provider's
+// requests. It returns the provider so calls can be chained at construction.
+func (p *openAIProvider) WithTools(tools []openAITool) *openAIProvider {
+	p.tools = tools
+	return p
+}
+
+func (p *openAIProvider) Name() string { return "openai" }
+
+func (p *openAIProvider) Close() error { return nil }
+
+func (p *openAIProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	endpoint := p.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = openAIEndpoint
+	}
+
+	return withRetry(ctx, p.cfg.MaxRetries, func() (string, error) {
+		reqBody := openAIRequest{
+			Model: p.cfg.Model,
+			Messages: []chatMessage{
+				{Role: "system", Content: "Be precise and concise."},
+				{Role: "user", Content: prompt},
+			},
+			Tools:  p.tools,
+			Stream: p.cfg.Stream,
+		}
+		payload, err := json.Marshal(reqBody)
+		if err != nil {
+			return "", fmt.Errorf("openai: marshalling request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
+		if err != nil {
+			return "", fmt.Errorf("openai: building request: %w", err)
+		}
+		req.Header.Set("content-type", "application/json")
+		req.Header.Set("authorization", "Bearer "+p.cfg.APIKey)
+
+		res, err := p.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("openai: request failed: %w", err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode >= 400 {
+			body, _ := io.ReadAll(res.Body)
+			return "", fmt.Errorf("openai: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		if p.cfg.Stream {
+			return p.readStream(res.Body)
+		}
+		return p.readChatResponse(res.Body)
+	})
+}
+
+func (p *openAIProvider) readChatResponse(r io.Reader) (string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("openai: reading response: %w", err)
+	}
+
+	var chatRes chatResponse
+	if err := json.Unmarshal(body, &chatRes); err != nil {
+		return "", fmt.Errorf("openai: unmarshalling response: %w", err)
+	}
+	if len(chatRes.Choices) == 0 {
+		return "", fmt.Errorf("openai: no choices in response")
+	}
+
+	p.mu.Lock()
+	p.lastUsage = Usage{
+		PromptTokens:     chatRes.Usage.PromptTokens,
+		CompletionTokens: chatRes.Usage.CompletionTokens,
+		TotalTokens:      chatRes.Usage.TotalTokens,
+	}
+	p.mu.Unlock()
+
+	return strings.TrimSpace(chatRes.Choices[0].Message.Content), nil
+}
+
+func (p *openAIProvider) readStream(r io.Reader) (string, error) {
+	var sb strings.Builder
+	err := readSSE(r, func(data string) error {
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("openai: unmarshalling stream chunk: %w", err)
+		}
+		if len(chunk.Choices) > 0 {
+			sb.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func (p *openAIProvider) LastUsage() Usage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastUsage
+}
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const perplexityEndpoint = "https://api.perplexity.ai/chat/completions"
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatChoice struct {
+	Index        int         `json:"index"`
+	FinishReason string      `json:"finish_reason"`
+	Message      chatMessage `json:"message"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type chatResponse struct {
+	ID      string       `json:"id"`
+	Model   string       `json:"model"`
+	Created int          `json:"created"`
+	Usage   chatUsage    `json:"usage"`
+	Object  string       `json:"object"`
+	Choices []chatChoice `json:"choices"`
+}
+
+// perplexityProvider talks to Perplexity's OpenAI-compatible chat
+// completions API.
+type perplexityProvider struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	lastUsage Usage
+}
+
+func newPerplexityProvider(cfg Config) *perplexityProvider {
+	return &perplexityProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+	}
+}
+
+func (p *perplexityProvider) Name() string { return "perplexity" }
+
+func (p *perplexityProvider) Close() error { return nil }
+
+func (p *perplexityProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	endpoint := p.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = perplexityEndpoint
+	}
+
+	return withRetry(ctx, p.cfg.MaxRetries, func() (string, error) {
+		reqBody := chatRequest{
+			Model: p.cfg.Model,
+			Messages: []chatMessage{
+				{Role: "system", Content: "Be precise and concise."},
+				{Role: "user", Content: prompt},
+			},
+		}
+		payload, err := json.Marshal(reqBody)
+		if err != nil {
+			return "", fmt.Errorf("perplexity: marshalling request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
+		if err != nil {
+			return "", fmt.Errorf("perplexity: building request: %w", err)
+		}
+		req.Header.Set("accept", "application/json")
+		req.Header.Set("content-type", "application/json")
+		req.Header.Set("authorization", "Bearer "+p.cfg.APIKey)
+
+		res, err := p.client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("perplexity: request failed: %w", err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return "", fmt.Errorf("perplexity: reading response: %w", err)
+		}
+		if res.StatusCode >= 400 {
+			return "", fmt.Errorf("perplexity: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+
+		var chatRes chatResponse
+		if err := json.Unmarshal(body, &chatRes); err != nil {
+			return "", fmt.Errorf("perplexity: unmarshalling response: %w", err)
+		}
+		if len(chatRes.Choices) == 0 {
+			return "", errors.New("perplexity: no choices in response")
+		}
+
+		p.mu.Lock()
+		p.lastUsage = Usage{
+			PromptTokens:     chatRes.Usage.PromptTokens,
+			CompletionTokens: chatRes.Usage.CompletionTokens,
+			TotalTokens:      chatRes.Usage.TotalTokens,
+		}
+		p.mu.Unlock()
+
+		return strings.TrimSpace(chatRes.Choices[0].Message.Content), nil
+	})
+}
+
+func (p *perplexityProvider) LastUsage() Usage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastUsage
+}
@@ -0,0 +1,83 @@
+// Package llm provides the pluggable LLM backends galah uses to craft
+// honeypot responses. Each backend implements Provider; which one is
+// active is selected at runtime via Config.Provider, so operators can
+// swap models without recompiling.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Provider is implemented by every LLM backend galah can use to generate
+// a response for an incoming request.
+type Provider interface {
+	// Generate returns the model's completion for prompt. Callers should
+	// pass a ctx with a deadline; implementations must honor cancellation.
+	Generate(ctx context.Context, prompt string) (string, error)
+	// Name returns the provider's identifier, as used in config.yaml.
+	Name() string
+	// Close releases any resources held by the provider (idle connections,
+	// background goroutines, etc).
+	Close() error
+}
+
+// UsageReporter is optionally implemented by providers that can report
+// token accounting for the most recently completed Generate call. galah
+// logs this into Event when available.
+type UsageReporter interface {
+	LastUsage() Usage
+}
+
+// Usage captures token accounting returned by a provider.
+type Usage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// Config configures provider construction. It mirrors the LLM-related
+// fields of the top-level Config in config.yaml.
+type Config struct {
+	Provider       string
+	Model          string
+	APIKey         string
+	Endpoint       string
+	PromptTemplate string
+	MaxRetries     int
+	RequestTimeout time.Duration
+	Stream         bool
+}
+
+const (
+	defaultRequestTimeout = 30 * time.Second
+	defaultMaxRetries     = 3
+)
+
+// New constructs the Provider named by cfg.Provider.
+func New(cfg Config) (Provider, error) {
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = defaultRequestTimeout
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+
+	switch cfg.Provider {
+	case "", "perplexity":
+		return newPerplexityProvider(cfg), nil
+	case "openai":
+		return newOpenAIProvider(cfg), nil
+	case "anthropic":
+		return newAnthropicProvider(cfg), nil
+	case "ollama":
+		return newOllamaProvider(cfg), nil
+	case "llamacpp":
+		return newLlamaCppProvider(cfg), nil
+	case "mock":
+		return NewMock(""), nil
+	default:
+		return nil, fmt.Errorf("llm: unknown provider %q", cfg.Provider)
+	}
+}
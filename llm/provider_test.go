@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMockGenerate(t *testing.T) {
+	m := NewMock("crafted response")
+
+	got, err := m.Generate(context.Background(), "GET /admin HTTP/1.1")
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+	if got != "crafted response" {
+		t.Errorf("Generate() = %q, want %q", got, "crafted response")
+	}
+	if len(m.Prompts) != 1 || m.Prompts[0] != "GET /admin HTTP/1.1" {
+		t.Errorf("Prompts recorded = %v, want one entry with the prompt", m.Prompts)
+	}
+}
+
+func TestMockGenerateError(t *testing.T) {
+	wantErr := errors.New("boom")
+	m := &Mock{Err: wantErr}
+
+	_, err := m.Generate(context.Background(), "prompt")
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Generate() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	_, err := New(Config{Provider: "carrier-pigeon"})
+	if err == nil {
+		t.Fatal("New() with unknown provider: expected error, got nil")
+	}
+}
+
+func TestNewDefaultsToPerplexity(t *testing.T) {
+	p, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if p.Name() != "perplexity" {
+		t.Errorf("New() with no provider set, Name() = %q, want %q", p.Name(), "perplexity")
+	}
+}
@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"context"
+	"time"
+)
+
+const initialBackoff = 500 * time.Millisecond
+
+// withRetry calls fn up to maxRetries+1 times, backing off exponentially
+// between attempts, and gives up early if ctx is done.
+func withRetry(ctx context.Context, maxRetries int, fn func() (string, error)) (string, error) {
+	backoff := initialBackoff
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if attempt == maxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return "", lastErr
+}
@@ -0,0 +1,34 @@
+package llm
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// readSSE reads a Server-Sent-Events stream from r, invoking onData for the
+// payload of every "data: " line. It stops at the first "data: [DONE]"
+// marker or when the stream ends.
+func readSSE(r io.Reader, onData func(data string) error) error {
+	scanner := bufio.NewScanner(r)
+	// Responses can include long tool-call payloads; grow past bufio's
+	// default 64KB line limit.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			return nil
+		}
+		if err := onData(data); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,58 @@
+// Package logging provides galah's structured logger. It replaces the
+// standard library's log package for what galah's main package logs
+// after config.yaml is loaded, so those lines carry structured fields
+// (port, srcIP, provider, ...) a SIEM or log aggregator can index on,
+// instead of a free-form sentence. The output package does not use it:
+// its sink write failures are still logged with the standard library's
+// log.Printf.
+package logging
+
+import "fmt"
+
+// Logger is implemented by every logging backend galah can use.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	// Fatal logs msg at error level and then terminates the process,
+	// mirroring the standard library's log.Fatal.
+	Fatal(msg string, fields ...Field)
+}
+
+// Field is a single structured key/value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F constructs a Field, the usual way callers attach context to a line:
+// logger.Info("starting server", logging.F("port", pc.Port))
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Config selects and configures the logging backend.
+type Config struct {
+	Backend string `yaml:"backend"` // "zerolog" (default) or "logrus"
+	Level   string `yaml:"level"`   // "debug", "info", "warn", "error"; defaults to "info"
+}
+
+const defaultLevel = "info"
+
+// New constructs the Logger named by cfg.Backend.
+func New(cfg Config) (Logger, error) {
+	level := cfg.Level
+	if level == "" {
+		level = defaultLevel
+	}
+
+	switch cfg.Backend {
+	case "", "zerolog":
+		return newZerologLogger(level)
+	case "logrus":
+		return newLogrusLogger(level)
+	default:
+		return nil, fmt.Errorf("logging: unknown backend %q", cfg.Backend)
+	}
+}
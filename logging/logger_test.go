@@ -0,0 +1,37 @@
+package logging
+
+import "testing"
+
+func TestNewUnknownBackend(t *testing.T) {
+	_, err := New(Config{Backend: "fluentd"})
+	if err == nil {
+		t.Fatal("New() with unknown backend: expected error, got nil")
+	}
+}
+
+func TestNewDefaultsToZerolog(t *testing.T) {
+	l, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if _, ok := l.(*zerologLogger); !ok {
+		t.Errorf("New() with no backend set, got %T, want *zerologLogger", l)
+	}
+}
+
+func TestNewLogrusBackend(t *testing.T) {
+	l, err := New(Config{Backend: "logrus"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	if _, ok := l.(*logrusLogger); !ok {
+		t.Errorf("New() with logrus backend, got %T, want *logrusLogger", l)
+	}
+}
+
+func TestNewInvalidLevel(t *testing.T) {
+	_, err := New(Config{Level: "deafening"})
+	if err == nil {
+		t.Fatal("New() with invalid level: expected error, got nil")
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logrusLogger is the alternative Logger backend, for operators who
+// already run logrus elsewhere in their stack and want galah's log
+// format to match.
+type logrusLogger struct {
+	logger *logrus.Logger
+}
+
+func newLogrusLogger(level string) (Logger, error) {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("logging: parsing level %q: %w", level, err)
+	}
+
+	l := logrus.New()
+	l.SetLevel(lvl)
+	l.SetFormatter(&logrus.JSONFormatter{})
+
+	return &logrusLogger{logger: l}, nil
+}
+
+func toLogrusFields(fields []Field) logrus.Fields {
+	out := make(logrus.Fields, len(fields))
+	for _, f := range fields {
+		out[f.Key] = f.Value
+	}
+	return out
+}
+
+func (l *logrusLogger) Debug(msg string, fields ...Field) {
+	l.logger.WithFields(toLogrusFields(fields)).Debug(msg)
+}
+
+func (l *logrusLogger) Info(msg string, fields ...Field) {
+	l.logger.WithFields(toLogrusFields(fields)).Info(msg)
+}
+
+func (l *logrusLogger) Warn(msg string, fields ...Field) {
+	l.logger.WithFields(toLogrusFields(fields)).Warn(msg)
+}
+
+func (l *logrusLogger) Error(msg string, fields ...Field) {
+	l.logger.WithFields(toLogrusFields(fields)).Error(msg)
+}
+
+// Fatal logs at error level then calls os.Exit(1). It does not go through
+// logrus's own Fatal, which would write the entry at "fatal" level.
+func (l *logrusLogger) Fatal(msg string, fields ...Field) {
+	l.logger.WithFields(toLogrusFields(fields)).Error(msg)
+	os.Exit(1)
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// zerologLogger is the default Logger backend: zerolog's allocation-free
+// JSON encoder is the cheaper of the two on galah's hot path, where a
+// request can log even when nothing unusual happened (app.Verbose mode).
+type zerologLogger struct {
+	logger zerolog.Logger
+}
+
+func newZerologLogger(level string) (Logger, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("logging: parsing level %q: %w", level, err)
+	}
+	return &zerologLogger{
+		logger: zerolog.New(os.Stdout).Level(lvl).With().Timestamp().Logger(),
+	}, nil
+}
+
+func withFields(e *zerolog.Event, fields []Field) *zerolog.Event {
+	for _, f := range fields {
+		e = e.Interface(f.Key, f.Value)
+	}
+	return e
+}
+
+func (l *zerologLogger) Debug(msg string, fields ...Field) {
+	withFields(l.logger.Debug(), fields).Msg(msg)
+}
+
+func (l *zerologLogger) Info(msg string, fields ...Field) {
+	withFields(l.logger.Info(), fields).Msg(msg)
+}
+
+func (l *zerologLogger) Warn(msg string, fields ...Field) {
+	withFields(l.logger.Warn(), fields).Msg(msg)
+}
+
+func (l *zerologLogger) Error(msg string, fields ...Field) {
+	withFields(l.logger.Error(), fields).Msg(msg)
+}
+
+// Fatal logs at error level then calls os.Exit(1). It does not go through
+// zerolog's own Fatal, which would write the entry at "fatal" level
+// rather than the error level the Logger interface documents.
+func (l *zerologLogger) Fatal(msg string, fields ...Field) {
+	withFields(l.logger.Error(), fields).Msg(msg)
+	os.Exit(1)
+}
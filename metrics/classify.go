@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var httpStatusPattern = regexp.MustCompile(`status (\d)\d\d`)
+
+// ClassifyLLMError buckets an error returned by an llm.Provider into one
+// of galah's error-count labels, so operators can tell "the model is
+// slow" apart from "the model is down" apart from "the model is
+// returning garbage" at a glance on the dashboard.
+func ClassifyLLMError(err error) string {
+	if err == nil {
+		return ClassOther
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ClassTimeout
+	}
+
+	msg := err.Error()
+	if strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded") {
+		return ClassTimeout
+	}
+
+	if m := httpStatusPattern.FindStringSubmatch(msg); m != nil {
+		switch m[1] {
+		case "4":
+			return Class4xx
+		case "5":
+			return Class5xx
+		}
+	}
+
+	if strings.Contains(msg, "unmarshal") || strings.Contains(msg, "decoding") || strings.Contains(msg, "parsing") {
+		return ClassParseFailure
+	}
+
+	return ClassOther
+}
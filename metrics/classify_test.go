@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyLLMError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"deadline", context.DeadlineExceeded, ClassTimeout},
+		{"timeout message", errors.New("ollama: request failed: context deadline exceeded (timeout)"), ClassTimeout},
+		{"4xx", fmt.Errorf("openai: unexpected status 429: rate limited"), Class4xx},
+		{"5xx", fmt.Errorf("anthropic: unexpected status 503: overloaded"), Class5xx},
+		{"parse failure", fmt.Errorf("ollama: unmarshalling response: unexpected end of JSON"), ClassParseFailure},
+		{"other", errors.New("connection refused"), ClassOther},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ClassifyLLMError(tc.err); got != tc.want {
+				t.Errorf("ClassifyLLMError(%v) = %q, want %q", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAtomicCounter(t *testing.T) {
+	var c atomicCounter
+	c.Add(3)
+	c.Add(4)
+	if got := c.Load(); got != 7 {
+		t.Errorf("Load() = %d, want 7", got)
+	}
+}
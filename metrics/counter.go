@@ -0,0 +1,18 @@
+package metrics
+
+import "sync/atomic"
+
+// atomicCounter is a monotonically increasing counter safe for
+// concurrent use without locking, the building block every hot-path
+// counter in Registry is made of.
+type atomicCounter struct {
+	v uint64
+}
+
+func (c *atomicCounter) Add(delta uint64) {
+	atomic.AddUint64(&c.v, delta)
+}
+
+func (c *atomicCounter) Load() uint64 {
+	return atomic.LoadUint64(&c.v)
+}
@@ -0,0 +1,18 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler returns the /metrics HTTP handler for r, backed by a dedicated
+// registry (not prometheus.DefaultRegisterer) so galah's process doesn't
+// also expose the Go runtime/process collectors client_golang registers
+// globally by default.
+func (r *Registry) Handler() http.Handler {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(r)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
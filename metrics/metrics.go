@@ -0,0 +1,213 @@
+// Package metrics exposes galah's Prometheus /metrics endpoint.
+//
+// The counters callers bump from the request-handling hot path
+// (requests per port, cache hit/miss, LLM error class, token totals) are
+// plain sync/atomic counters, not prometheus.CounterVec.Inc() calls: a
+// CounterVec lookup takes an internal lock to find (or create) the
+// label's series, which is unnecessary contention on a path that already
+// knows every label value it will ever see up front. Registry
+// pre-allocates one atomic counter per label value at construction time
+// and implements prometheus.Collector itself, so the Prometheus scrape
+// path (Collect) is the only place that turns those counters into
+// metrics.
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/0x4d31/galah/session"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LLM error classes, the labels galah's LLM error counter is broken down
+// by.
+const (
+	ClassTimeout      = "timeout"
+	Class4xx          = "4xx"
+	Class5xx          = "5xx"
+	ClassParseFailure = "parse-failure"
+	ClassOther        = "other"
+)
+
+var llmErrorClasses = []string{ClassTimeout, Class4xx, Class5xx, ClassParseFailure, ClassOther}
+
+// Registry holds every counter galah exposes on /metrics, plus the
+// shared state (DB, session store) it reads from at scrape time for
+// metrics that aren't worth tracking incrementally.
+type Registry struct {
+	db       *sql.DB
+	sessions *session.Store
+
+	requestsByPort map[uint16]*atomicCounter
+	requestsOther  atomicCounter // ports outside the configured set
+
+	cacheHits   atomicCounter
+	cacheMisses atomicCounter
+
+	llmErrorsByClass map[string]*atomicCounter
+
+	tokensPrompt     atomicCounter
+	tokensCompletion atomicCounter
+	tokensTotal      atomicCounter
+
+	llmLatency *prometheus.HistogramVec
+
+	descRequests  *prometheus.Desc
+	descCache     *prometheus.Desc
+	descLLMErrors *prometheus.Desc
+	descTokens    *prometheus.Desc
+	descCacheSize *prometheus.Desc
+	descSessions  *prometheus.Desc
+	descIPRate    *prometheus.Desc
+}
+
+// New builds a Registry for the configured listener ports. db and
+// sessions are read (never written) at scrape time, for the SQLite
+// cache size, active session count, and per-source-IP request rate.
+func New(db *sql.DB, sessions *session.Store, ports []uint16) *Registry {
+	r := &Registry{
+		db:               db,
+		sessions:         sessions,
+		requestsByPort:   make(map[uint16]*atomicCounter, len(ports)),
+		llmErrorsByClass: make(map[string]*atomicCounter, len(llmErrorClasses)),
+
+		llmLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "galah",
+			Name:      "llm_request_duration_seconds",
+			Help:      "LLM provider request latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"provider"}),
+
+		descRequests:  prometheus.NewDesc("galah_requests_total", "Total HTTP requests handled, by port.", []string{"port"}, nil),
+		descCache:     prometheus.NewDesc("galah_cache_results_total", "Response cache lookups, by result.", []string{"result"}, nil),
+		descLLMErrors: prometheus.NewDesc("galah_llm_errors_total", "LLM provider errors, by class.", []string{"class"}, nil),
+		descTokens:    prometheus.NewDesc("galah_llm_tokens_total", "LLM token usage, by kind.", []string{"kind"}, nil),
+		descCacheSize: prometheus.NewDesc("galah_sqlite_cache_rows", "Number of rows in the SQLite response cache.", nil, nil),
+		descSessions:  prometheus.NewDesc("galah_active_sessions", "Number of active attacker sessions.", nil, nil),
+		descIPRate:    prometheus.NewDesc("galah_session_request_rate", "Requests per second, per active session's source IP.", []string{"srcIP"}, nil),
+	}
+
+	for _, p := range ports {
+		r.requestsByPort[p] = &atomicCounter{}
+	}
+	for _, c := range llmErrorClasses {
+		r.llmErrorsByClass[c] = &atomicCounter{}
+	}
+
+	return r
+}
+
+// IncRequest records one HTTP request received on port.
+func (r *Registry) IncRequest(port uint16) {
+	if c, ok := r.requestsByPort[port]; ok {
+		c.Add(1)
+		return
+	}
+	r.requestsOther.Add(1)
+}
+
+// ObserveCacheResult records a response cache lookup outcome.
+func (r *Registry) ObserveCacheResult(hit bool) {
+	if hit {
+		r.cacheHits.Add(1)
+	} else {
+		r.cacheMisses.Add(1)
+	}
+}
+
+// ObserveLLMLatency records how long an LLM provider took to respond.
+// It goes through prometheus.HistogramVec directly rather than an atomic
+// counter: one LLM call per cache-missed request is orders of magnitude
+// rarer than the per-request counters above, so the label-lookup lock
+// isn't meaningful contention here.
+func (r *Registry) ObserveLLMLatency(provider string, d time.Duration) {
+	r.llmLatency.WithLabelValues(provider).Observe(d.Seconds())
+}
+
+// IncLLMError records an LLM provider error, classified by ClassifyLLMError.
+func (r *Registry) IncLLMError(class string) {
+	c, ok := r.llmErrorsByClass[class]
+	if !ok {
+		c = r.llmErrorsByClass[ClassOther]
+	}
+	c.Add(1)
+}
+
+// AddTokens records one LLM call's token accounting.
+func (r *Registry) AddTokens(prompt, completion, total int) {
+	r.tokensPrompt.Add(uint64(prompt))
+	r.tokensCompletion.Add(uint64(completion))
+	r.tokensTotal.Add(uint64(total))
+}
+
+// Describe implements prometheus.Collector.
+func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.descRequests
+	ch <- r.descCache
+	ch <- r.descLLMErrors
+	ch <- r.descTokens
+	ch <- r.descCacheSize
+	ch <- r.descSessions
+	ch <- r.descIPRate
+	r.llmLatency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector: it's the only place that reads
+// the atomic counters above, and the only place that touches the DB or
+// session store for metrics, so a slow scrape can never show up as
+// latency on the request path.
+func (r *Registry) Collect(ch chan<- prometheus.Metric) {
+	for port, c := range r.requestsByPort {
+		ch <- prometheus.MustNewConstMetric(r.descRequests, prometheus.CounterValue, float64(c.Load()), portLabel(port))
+	}
+	ch <- prometheus.MustNewConstMetric(r.descRequests, prometheus.CounterValue, float64(r.requestsOther.Load()), "other")
+
+	ch <- prometheus.MustNewConstMetric(r.descCache, prometheus.CounterValue, float64(r.cacheHits.Load()), "hit")
+	ch <- prometheus.MustNewConstMetric(r.descCache, prometheus.CounterValue, float64(r.cacheMisses.Load()), "miss")
+
+	for _, class := range llmErrorClasses {
+		ch <- prometheus.MustNewConstMetric(r.descLLMErrors, prometheus.CounterValue, float64(r.llmErrorsByClass[class].Load()), class)
+	}
+
+	ch <- prometheus.MustNewConstMetric(r.descTokens, prometheus.CounterValue, float64(r.tokensPrompt.Load()), "prompt")
+	ch <- prometheus.MustNewConstMetric(r.descTokens, prometheus.CounterValue, float64(r.tokensCompletion.Load()), "completion")
+	ch <- prometheus.MustNewConstMetric(r.descTokens, prometheus.CounterValue, float64(r.tokensTotal.Load()), "total")
+
+	if r.db != nil {
+		var rows float64
+		if err := r.db.QueryRow("SELECT COUNT(*) FROM cache").Scan(&rows); err == nil {
+			ch <- prometheus.MustNewConstMetric(r.descCacheSize, prometheus.GaugeValue, rows)
+		}
+	}
+
+	if r.sessions != nil {
+		sessions := r.sessions.All()
+		ch <- prometheus.MustNewConstMetric(r.descSessions, prometheus.GaugeValue, float64(len(sessions)))
+
+		// Sessions are keyed by (srcIP, UA, JA3), so a single srcIP (e.g. a
+		// scanner varying its User-Agent, or several tools behind one NAT'd
+		// IP) can own more than one session. Aggregate to one rate per
+		// srcIP before emitting: a second prometheus.MustNewConstMetric
+		// with the same label set would make the whole /metrics scrape
+		// fail, not just that series.
+		ratesByIP := make(map[string]float64, len(sessions))
+		for _, s := range sessions {
+			elapsed := s.LastSeen.Sub(s.FirstSeen).Seconds()
+			if elapsed <= 0 {
+				elapsed = 1
+			}
+			ratesByIP[s.SrcIP] += float64(s.RequestCount) / elapsed
+		}
+		for srcIP, rate := range ratesByIP {
+			ch <- prometheus.MustNewConstMetric(r.descIPRate, prometheus.GaugeValue, rate, srcIP)
+		}
+	}
+
+	r.llmLatency.Collect(ch)
+}
+
+func portLabel(port uint16) string {
+	return strconv.FormatUint(uint64(port), 10)
+}
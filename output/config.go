@@ -0,0 +1,61 @@
+package output
+
+import "fmt"
+
+// Config configures the set of sinks a Manager fans events out to. Each
+// non-empty sub-config enables the corresponding sink; any number of them
+// can be active at once.
+type Config struct {
+	File          *FileConfig          `yaml:"file"`
+	Elasticsearch *ElasticsearchConfig `yaml:"elasticsearch"`
+	Kafka         *KafkaConfig         `yaml:"kafka"`
+	Syslog        *SyslogConfig        `yaml:"syslog"`
+}
+
+// FileConfig configures the newline-delimited JSON file sink.
+type FileConfig struct {
+	Path string `yaml:"path"`
+}
+
+// New builds the Sinks enabled in cfg and returns a Manager fanning events
+// out to all of them. If cfg is empty, New falls back to a single
+// FileSink at defaultPath so galah keeps its original behavior out of
+// the box.
+func New(cfg Config, defaultPath string) (*Manager, error) {
+	var sinks []Sink
+
+	if cfg.File != nil {
+		path := cfg.File.Path
+		if path == "" {
+			path = defaultPath
+		}
+		sinks = append(sinks, NewFileSink(path))
+	}
+	if cfg.Elasticsearch != nil {
+		sink, err := newElasticsearchSink(*cfg.Elasticsearch)
+		if err != nil {
+			return nil, fmt.Errorf("output: configuring elasticsearch sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Kafka != nil {
+		sink, err := newKafkaSink(*cfg.Kafka)
+		if err != nil {
+			return nil, fmt.Errorf("output: configuring kafka sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Syslog != nil {
+		sink, err := newSyslogSink(*cfg.Syslog)
+		if err != nil {
+			return nil, fmt.Errorf("output: configuring syslog sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	if len(sinks) == 0 {
+		sinks = append(sinks, NewFileSink(defaultPath))
+	}
+
+	return NewManager(sinks), nil
+}
@@ -0,0 +1,47 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink appends newline-delimited JSON events to a file. This is
+// galah's original (and default) output behavior.
+type FileSink struct {
+	path string
+
+	mu sync.Mutex
+}
+
+// NewFileSink returns a FileSink that appends to path, creating it if
+// necessary.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (f *FileSink) Write(event any) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("output: marshalling event: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("output: opening %s: %w", f.path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("output: writing to %s: %w", f.path, err)
+	}
+	return nil
+}
+
+func (f *FileSink) Flush() error { return nil }
+
+func (f *FileSink) Close() error { return nil }
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaConfig configures the Kafka producer sink.
+type KafkaConfig struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+	// PartitionKey selects an event field ("srcIP" or "sensorName") to
+	// derive the partition key from, so events from the same attacker or
+	// sensor land on the same partition. Defaults to "srcIP".
+	PartitionKey string `yaml:"partition_key"`
+}
+
+const defaultPartitionKey = "srcIP"
+
+// kafkaSink produces events to a Kafka topic, keyed by PartitionKey so
+// related events stay ordered within a partition.
+type kafkaSink struct {
+	cfg    KafkaConfig
+	writer *kafka.Writer
+}
+
+func newKafkaSink(cfg KafkaConfig) (*kafkaSink, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("kafka sink: no brokers configured")
+	}
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka sink: no topic configured")
+	}
+	if cfg.PartitionKey == "" {
+		cfg.PartitionKey = defaultPartitionKey
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+		BatchTimeout: 1 * time.Second,
+	}
+
+	return &kafkaSink{cfg: cfg, writer: writer}, nil
+}
+
+func (s *kafkaSink) Write(event any) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("kafka: marshalling event: %w", err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(s.partitionKey(value)),
+		Value: value,
+	}
+	if err := s.writer.WriteMessages(context.Background(), msg); err != nil {
+		return fmt.Errorf("kafka: writing message: %w", err)
+	}
+	return nil
+}
+
+// partitionKey pulls cfg.PartitionKey out of the already-marshalled event
+// JSON, rather than requiring callers to pass galah's Event type in (this
+// package doesn't import it, to stay reusable outside galah).
+func (s *kafkaSink) partitionKey(eventJSON []byte) string {
+	var fields map[string]any
+	if err := json.Unmarshal(eventJSON, &fields); err != nil {
+		return ""
+	}
+	v, _ := fields[s.cfg.PartitionKey].(string)
+	return v
+}
+
+func (s *kafkaSink) Flush() error { return nil }
+
+func (s *kafkaSink) Close() error { return s.writer.Close() }
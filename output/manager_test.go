@@ -0,0 +1,88 @@
+package output
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSink struct {
+	mu     sync.Mutex
+	events []any
+	err    error
+	closed bool
+}
+
+func (f *fakeSink) Write(event any) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeSink) Flush() error { return nil }
+
+func (f *fakeSink) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeSink) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.events)
+}
+
+func TestManagerFansOutToEverySink(t *testing.T) {
+	a, b := &fakeSink{}, &fakeSink{}
+	m := NewManager([]Sink{a, b})
+
+	m.Enqueue(map[string]string{"srcIP": "1.2.3.4"})
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if a.count() != 1 || b.count() != 1 {
+		t.Errorf("sink event counts = %d, %d, want 1, 1", a.count(), b.count())
+	}
+	if !a.closed || !b.closed {
+		t.Error("expected Close() to close every sink")
+	}
+}
+
+func TestManagerCountsWriteErrors(t *testing.T) {
+	bad := &fakeSink{err: errors.New("boom")}
+	m := NewManager([]Sink{bad})
+
+	m.Enqueue("event")
+	waitForHealth(t, m, func(h Health) bool { return h.WriteErrors == 1 })
+}
+
+func TestManagerDropsWhenQueueFull(t *testing.T) {
+	// No loop goroutine running, so nothing drains events: the channel's
+	// capacity is the only thing standing between Enqueue and a drop.
+	m := &Manager{events: make(chan any, 1)}
+
+	m.Enqueue("fits")
+	m.Enqueue("dropped")
+
+	if got := m.Health().Dropped; got != 1 {
+		t.Errorf("Dropped = %d, want 1", got)
+	}
+}
+
+func waitForHealth(t *testing.T, m *Manager, cond func(Health) bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond(m.Health()) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Health() never satisfied condition, got %+v", m.Health())
+}
@@ -0,0 +1,31 @@
+package output
+
+import "time"
+
+const (
+	defaultMaxRetries   = 3
+	retryInitialBackoff = 500 * time.Millisecond
+)
+
+// retry calls fn up to maxRetries+1 times, backing off exponentially
+// between attempts.
+func retry(maxRetries int, fn func() error) error {
+	backoff := retryInitialBackoff
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err := fn(); err == nil {
+			return nil
+		} else {
+			lastErr = err
+		}
+
+		if attempt == maxRetries {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return lastErr
+}
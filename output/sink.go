@@ -0,0 +1,128 @@
+// Package output provides pluggable destinations for honeypot events.
+// galah ships a Sink implementation per destination (file, Elasticsearch,
+// Kafka, syslog); a Manager fans events out to however many are
+// configured at once over a bounded queue; once a stuck sink lets that
+// queue fill, further events are dropped and counted instead of blocking
+// the HTTP handlers.
+package output
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// Sink is implemented by every event destination galah can write to.
+// Implementations marshal the event themselves, so this package never
+// needs to import galah's Event type.
+type Sink interface {
+	Write(event any) error
+	// Flush forces any buffered writes out immediately.
+	Flush() error
+	// Close flushes and releases the sink's resources.
+	Close() error
+}
+
+// Health is a snapshot of a Manager's delivery counters.
+type Health struct {
+	Written     uint64 `json:"written"`
+	WriteErrors uint64 `json:"writeErrors"`
+	Dropped     uint64 `json:"dropped"`
+}
+
+const defaultQueueSize = 10_000
+
+// Manager fans a stream of events out to every configured Sink over a
+// bounded channel and a single background flusher goroutine.
+type Manager struct {
+	sinks []Sink
+
+	events chan any
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	written     atomic.Uint64
+	writeErrors atomic.Uint64
+	dropped     atomic.Uint64
+}
+
+// NewManager starts a Manager delivering events to sinks.
+func NewManager(sinks []Sink) *Manager {
+	m := &Manager{
+		sinks:  sinks,
+		events: make(chan any, defaultQueueSize),
+		done:   make(chan struct{}),
+	}
+	m.wg.Add(1)
+	go m.loop()
+	return m
+}
+
+// Enqueue queues event for delivery to every sink. It never blocks: if
+// the queue is full, the event is dropped and counted rather than
+// stalling the caller (the HTTP handler that produced it).
+func (m *Manager) Enqueue(event any) {
+	select {
+	case m.events <- event:
+	default:
+		m.dropped.Add(1)
+	}
+}
+
+// Health returns a snapshot of the manager's delivery counters.
+func (m *Manager) Health() Health {
+	return Health{
+		Written:     m.written.Load(),
+		WriteErrors: m.writeErrors.Load(),
+		Dropped:     m.dropped.Load(),
+	}
+}
+
+// Close stops the flusher goroutine, drains whatever is already queued,
+// and closes every sink.
+func (m *Manager) Close() error {
+	close(m.done)
+	m.wg.Wait()
+
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("output: errors closing sinks: %v", errs)
+	}
+	return nil
+}
+
+func (m *Manager) loop() {
+	defer m.wg.Done()
+	for {
+		select {
+		case event := <-m.events:
+			m.dispatch(event)
+		case <-m.done:
+			for {
+				select {
+				case event := <-m.events:
+					m.dispatch(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *Manager) dispatch(event any) {
+	for _, s := range m.sinks {
+		if err := s.Write(event); err != nil {
+			m.writeErrors.Add(1)
+			log.Printf("output: sink write failed: %v", err)
+			continue
+		}
+		m.written.Add(1)
+	}
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// SyslogConfig configures the RFC 5424 syslog sink.
+type SyslogConfig struct {
+	Network  string `yaml:"network"` // "udp", "tcp", or "tls"
+	Addr     string `yaml:"addr"`
+	Facility int    `yaml:"facility"`
+	Tag      string `yaml:"tag"`
+}
+
+const (
+	defaultSyslogFacility = 1 // user-level messages
+	defaultSyslogTag      = "galah"
+	syslogSeverityInfo    = 6
+	syslogDialTimeout     = 5 * time.Second
+)
+
+// syslogSink writes events as RFC 5424 messages to a syslog collector.
+// The connection is dialed lazily and redialed on write failure, so a
+// collector that is briefly unreachable doesn't take the sink down.
+type syslogSink struct {
+	cfg      SyslogConfig
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newSyslogSink(cfg SyslogConfig) (*syslogSink, error) {
+	switch cfg.Network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("syslog sink: unsupported network %q", cfg.Network)
+	}
+	if cfg.Addr == "" {
+		return nil, fmt.Errorf("syslog sink: no addr configured")
+	}
+	if cfg.Facility == 0 {
+		cfg.Facility = defaultSyslogFacility
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = defaultSyslogTag
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	return &syslogSink{cfg: cfg, hostname: hostname}, nil
+}
+
+func (s *syslogSink) Write(event any) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("syslog: marshalling event: %w", err)
+	}
+
+	msg := s.formatRFC5424(data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.dialLocked(); err != nil {
+			return fmt.Errorf("syslog: dialing %s: %w", s.cfg.Addr, err)
+		}
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("syslog: writing message: %w", err)
+	}
+	return nil
+}
+
+// formatRFC5424 wraps data, galah's JSON event, as the structured
+// message of an RFC 5424 syslog entry.
+func (s *syslogSink) formatRFC5424(data []byte) []byte {
+	priority := s.cfg.Facility*8 + syslogSeverityInfo
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	return []byte(fmt.Sprintf("<%d>1 %s %s %s - - - %s\n",
+		priority, timestamp, s.hostname, s.cfg.Tag, data))
+}
+
+func (s *syslogSink) dialLocked() error {
+	network := s.cfg.Network
+	if network == "tls" {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: syslogDialTimeout}, "tcp", s.cfg.Addr, nil)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		return nil
+	}
+
+	conn, err := net.DialTimeout(network, s.cfg.Addr, syslogDialTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *syslogSink) Flush() error { return nil }
+
+func (s *syslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
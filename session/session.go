@@ -0,0 +1,79 @@
+// Package session groups incoming honeypot requests into attacker
+// sessions so that a multi-request scan gets coherent, contextual LLM
+// responses instead of independently-hallucinated ones.
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Turn is one request/response pair recorded in a Session's transcript.
+// The transcript is fed back into the LLM prompt so later responses in
+// the same session stay consistent with earlier ones.
+type Turn struct {
+	Request  string    `json:"request"`
+	Response string    `json:"response"`
+	At       time.Time `json:"at"`
+}
+
+// Session is an attacker's activity grouped by source identity over a
+// rolling window of time.
+type Session struct {
+	ID           string          `json:"id"`
+	Key          string          `json:"-"`
+	SrcIP        string          `json:"srcIP"`
+	UserAgent    string          `json:"userAgent"`
+	JA3          string          `json:"ja3,omitempty"`
+	FirstSeen    time.Time       `json:"firstSeen"`
+	LastSeen     time.Time       `json:"lastSeen"`
+	RequestCount int             `json:"requestCount"`
+	URIs         map[string]bool `json:"-"`
+	Transcript   []Turn          `json:"transcript"`
+}
+
+// URIList returns the set of distinct URIs requested in the session.
+func (s *Session) URIList() []string {
+	uris := make([]string, 0, len(s.URIs))
+	for u := range s.URIs {
+		uris = append(uris, u)
+	}
+	return uris
+}
+
+// MarshalJSON surfaces the session's distinct URIs (via URIList) under
+// "uris", since the URIs set itself is tagged json:"-" (a map[string]bool
+// isn't a meaningful wire format for callers of the /sessions endpoint).
+func (s *Session) MarshalJSON() ([]byte, error) {
+	type alias Session
+	return json.Marshal(&struct {
+		URIs []string `json:"uris"`
+		*alias
+	}{
+		URIs:  s.URIList(),
+		alias: (*alias)(s),
+	})
+}
+
+// Key derives the grouping key for a request from the attacker's source
+// IP and User-Agent, plus their TLS JA3 fingerprint when available.
+// Requests that share a key within a Store's TTL are folded into the
+// same Session.
+func Key(srcIP, userAgent, ja3 string) string {
+	h := sha256.Sum256([]byte(srcIP + "|" + userAgent + "|" + ja3))
+	return hex.EncodeToString(h[:])
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand.Read on Linux only fails if the kernel's CSPRNG is
+		// unseeded or the read is interrupted; either way a zeroed ID is
+		// safer than crashing the honeypot.
+		return hex.EncodeToString(b)
+	}
+	return hex.EncodeToString(b)
+}
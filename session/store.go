@@ -0,0 +1,184 @@
+package session
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Config configures a Store.
+type Config struct {
+	// TTL is how long a session stays active without a new request
+	// before it is considered over and a fresh one is started for the
+	// same key.
+	TTL time.Duration
+	// CacheSize is the maximum number of sessions kept in memory. Once
+	// reached, the least-recently-touched session is evicted, mirroring
+	// galah's cacheSize constant for the enrichment lookup cache.
+	CacheSize int
+}
+
+// Store tracks in-progress attacker sessions, keyed by Key. It is safe
+// for concurrent use.
+type Store struct {
+	ttl       time.Duration
+	cacheSize int
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	lru      *list.List
+	elems    map[string]*list.Element
+}
+
+// New creates a Store from cfg.
+func New(cfg Config) *Store {
+	return &Store{
+		ttl:       cfg.TTL,
+		cacheSize: cfg.CacheSize,
+		sessions:  make(map[string]*Session),
+		lru:       list.New(),
+		elems:     make(map[string]*list.Element),
+	}
+}
+
+// Touch records a request against the session identified by key,
+// creating a new Session if none exists or the previous one expired,
+// and returns it. uri is added to the session's set of distinct URIs.
+func (s *Store) Touch(key, srcIP, userAgent, ja3, uri string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpiredLocked()
+
+	now := time.Now()
+	sess, ok := s.sessions[key]
+	if !ok || now.Sub(sess.LastSeen) > s.ttl {
+		sess = &Session{
+			ID:        newSessionID(),
+			Key:       key,
+			SrcIP:     srcIP,
+			UserAgent: userAgent,
+			JA3:       ja3,
+			FirstSeen: now,
+			URIs:      make(map[string]bool),
+		}
+		s.sessions[key] = sess
+	}
+
+	sess.LastSeen = now
+	sess.RequestCount++
+	sess.URIs[uri] = true
+
+	s.touchLRULocked(key)
+	s.evictLRULocked()
+
+	return sess
+}
+
+// Record appends a request/response turn to the session's transcript.
+func (s *Store) Record(key, request, response string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[key]
+	if !ok {
+		return
+	}
+	sess.Transcript = append(sess.Transcript, Turn{
+		Request:  request,
+		Response: response,
+		At:       time.Now(),
+	})
+}
+
+// Transcript returns a copy of the session's recorded turns, oldest
+// first, for feeding back into the LLM prompt.
+func (s *Store) Transcript(key string) []Turn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[key]
+	if !ok {
+		return nil
+	}
+	turns := make([]Turn, len(sess.Transcript))
+	copy(turns, sess.Transcript)
+	return turns
+}
+
+// All returns a snapshot of every active session, for the admin
+// /sessions endpoint and for metrics collection. Each Session is a deep
+// copy taken under the store's lock, so the caller can read or marshal
+// it without racing Touch/Record mutating the live session concurrently.
+func (s *Store) All() []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpiredLocked()
+
+	out := make([]*Session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		out = append(out, copySessionLocked(sess))
+	}
+	return out
+}
+
+// copySessionLocked deep-copies sess, including its URIs set and
+// Transcript slice, so the result is safe to use after s.mu is released.
+func copySessionLocked(sess *Session) *Session {
+	cp := *sess
+
+	cp.URIs = make(map[string]bool, len(sess.URIs))
+	for uri, v := range sess.URIs {
+		cp.URIs[uri] = v
+	}
+
+	cp.Transcript = make([]Turn, len(sess.Transcript))
+	copy(cp.Transcript, sess.Transcript)
+
+	return &cp
+}
+
+func (s *Store) touchLRULocked(key string) {
+	if elem, ok := s.elems[key]; ok {
+		s.lru.MoveToFront(elem)
+		return
+	}
+	s.elems[key] = s.lru.PushFront(key)
+}
+
+func (s *Store) evictLRULocked() {
+	if s.cacheSize <= 0 {
+		return
+	}
+	for len(s.sessions) > s.cacheSize {
+		oldest := s.lru.Back()
+		if oldest == nil {
+			return
+		}
+		s.removeLocked(oldest.Value.(string))
+	}
+}
+
+func (s *Store) evictExpiredLocked() {
+	if s.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	for elem := s.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		key := elem.Value.(string)
+		if sess, ok := s.sessions[key]; ok && now.Sub(sess.LastSeen) > s.ttl {
+			s.removeLocked(key)
+		}
+		elem = prev
+	}
+}
+
+func (s *Store) removeLocked(key string) {
+	if elem, ok := s.elems[key]; ok {
+		s.lru.Remove(elem)
+		delete(s.elems, key)
+	}
+	delete(s.sessions, key)
+}
@@ -0,0 +1,68 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTouchGroupsBySameKey(t *testing.T) {
+	store := New(Config{TTL: time.Minute, CacheSize: 10})
+	key := Key("1.2.3.4", "curl/8.0", "")
+
+	first := store.Touch(key, "1.2.3.4", "curl/8.0", "", "/admin")
+	second := store.Touch(key, "1.2.3.4", "curl/8.0", "", "/admin/config.php")
+
+	if first.ID != second.ID {
+		t.Fatalf("expected requests with the same key to share a session, got %q and %q", first.ID, second.ID)
+	}
+	if second.RequestCount != 2 {
+		t.Errorf("RequestCount = %d, want 2", second.RequestCount)
+	}
+	if len(second.URIs) != 2 {
+		t.Errorf("len(URIs) = %d, want 2", len(second.URIs))
+	}
+}
+
+func TestTouchExpiresAfterTTL(t *testing.T) {
+	store := New(Config{TTL: time.Millisecond, CacheSize: 10})
+	key := Key("1.2.3.4", "curl/8.0", "")
+
+	first := store.Touch(key, "1.2.3.4", "curl/8.0", "", "/")
+	time.Sleep(5 * time.Millisecond)
+	second := store.Touch(key, "1.2.3.4", "curl/8.0", "", "/")
+
+	if first.ID == second.ID {
+		t.Fatal("expected a new session after the TTL elapsed")
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	store := New(Config{TTL: time.Minute, CacheSize: 2})
+
+	a := store.Touch(Key("1.1.1.1", "ua", ""), "1.1.1.1", "ua", "", "/")
+	_ = store.Touch(Key("2.2.2.2", "ua", ""), "2.2.2.2", "ua", "", "/")
+	_ = store.Touch(Key("3.3.3.3", "ua", ""), "3.3.3.3", "ua", "", "/")
+
+	all := store.All()
+	for _, sess := range all {
+		if sess.ID == a.ID {
+			t.Fatal("least-recently-used session should have been evicted")
+		}
+	}
+	if len(all) != 2 {
+		t.Errorf("len(All()) = %d, want 2", len(all))
+	}
+}
+
+func TestRecordAndTranscript(t *testing.T) {
+	store := New(Config{TTL: time.Minute, CacheSize: 10})
+	key := Key("1.2.3.4", "curl/8.0", "")
+	store.Touch(key, "1.2.3.4", "curl/8.0", "", "/")
+
+	store.Record(key, "GET /", "200 OK")
+	turns := store.Transcript(key)
+
+	if len(turns) != 1 || turns[0].Request != "GET /" {
+		t.Errorf("Transcript() = %+v, want one turn for %q", turns, "GET /")
+	}
+}
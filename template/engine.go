@@ -0,0 +1,137 @@
+// Package template lets operators define response skeletons for specific
+// routes, instead of relying purely on the LLM to produce a response from
+// scratch every time. A route's body is a Go text/template that can call
+// out to the LLM for the parts worth varying ({{llm "..."}}) while
+// pinning everything else — headers, static assets, timestamps — to
+// deterministic values the model can't get wrong or make too obviously
+// machine-generated (see the ignoreHeaders hack in galah.go, which
+// exists because LLM output leaks exactly these kinds of quirks).
+package template
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// RouteConfig is a single entry of the top-level config.yaml `templates`
+// list.
+type RouteConfig struct {
+	ID     string `yaml:"id"`
+	Method string `yaml:"method"`
+	Host   string `yaml:"host"`
+	Path   string `yaml:"path"`
+	Body   string `yaml:"body"`
+}
+
+// Route is a RouteConfig with its body pre-parsed, so a malformed
+// template fails fast at startup rather than on first request.
+type Route struct {
+	RouteConfig
+	tmpl *texttemplate.Template
+}
+
+// LLMResolver fills a single `{{llm "prompt"}}` slot for routeID. Engine
+// calls it through the `llm` template func; callers are expected to
+// cache on (routeID, prompt) themselves so repeat renders of the same
+// route don't re-hit the model.
+type LLMResolver func(ctx context.Context, routeID, prompt string) (string, error)
+
+// Engine matches incoming requests against a set of Routes and renders
+// whichever one matches.
+type Engine struct {
+	routes []*Route
+}
+
+// New parses every route's body up front and returns an Engine, or the
+// first parse error encountered.
+func New(cfgs []RouteConfig) (*Engine, error) {
+	e := &Engine{}
+	for _, cfg := range cfgs {
+		// The real function implementations are bound per-Render call
+		// (they close over that request's context and LLM resolver), but
+		// text/template requires every function a body calls to already
+		// exist at parse time. These placeholders exist solely to satisfy
+		// that check; Funcs is called again with the real ones before
+		// Execute, which text/template explicitly supports.
+		tmpl, err := texttemplate.New(cfg.ID).Funcs(placeholderFuncs()).Parse(cfg.Body)
+		if err != nil {
+			return nil, fmt.Errorf("template: parsing route %q: %w", cfg.ID, err)
+		}
+		e.routes = append(e.routes, &Route{RouteConfig: cfg, tmpl: tmpl})
+	}
+	return e, nil
+}
+
+func placeholderFuncs() texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"now":           func() string { return "" },
+		"uuid":          func() string { return "" },
+		"server_header": func(v string) string { return v },
+		"file":          func(string) (string, error) { return "", nil },
+		"llm":           func(string) (string, error) { return "", nil },
+	}
+}
+
+// Match returns the first route whose method, host, and path glob all
+// match the request, in configuration order — operators are expected to
+// order routes from most to least specific, the same convention
+// net/http's own ServeMux leaves to callers. An empty Method, Host, or
+// Path on a route matches anything.
+func (e *Engine) Match(method, host, requestPath string) (*Route, bool) {
+	for _, r := range e.routes {
+		if r.Method != "" && !strings.EqualFold(r.Method, method) {
+			continue
+		}
+		if r.Host != "" && !strings.EqualFold(r.Host, host) {
+			continue
+		}
+		if r.Path != "" {
+			ok, err := path.Match(r.Path, requestPath)
+			if err != nil || !ok {
+				continue
+			}
+		}
+		return r, true
+	}
+	return nil, false
+}
+
+// Render executes route's body, filling {{llm "..."}} slots via resolve
+// and the deterministic fields (now, uuid, server_header, file) with
+// fresh values on every call. Callers that want repeat hits to be cheap
+// should make resolve itself cache-backed — Render always re-executes
+// the template so now/uuid stay current, but a cache-backed resolve
+// means that doesn't cost a new LLM call each time.
+func (e *Engine) Render(ctx context.Context, route *Route, resolve LLMResolver) ([]byte, error) {
+	funcs := texttemplate.FuncMap{
+		"now":           func() string { return time.Now().UTC().Format(time.RFC1123) },
+		"uuid":          newUUID,
+		"server_header": func(v string) string { return v },
+		"file": func(p string) (string, error) {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return "", fmt.Errorf("template: reading %s: %w", p, err)
+			}
+			return jsonEscape(string(data)), nil
+		},
+		"llm": func(prompt string) (string, error) {
+			text, err := resolve(ctx, route.ID, prompt)
+			if err != nil {
+				return "", fmt.Errorf("template: resolving llm slot: %w", err)
+			}
+			return jsonEscape(text), nil
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := route.tmpl.Funcs(funcs).Execute(&buf, nil); err != nil {
+		return nil, fmt.Errorf("template: rendering route %q: %w", route.ID, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMatchMethodHostPath(t *testing.T) {
+	e, err := New([]RouteConfig{
+		{ID: "admin", Method: "GET", Path: "/admin/*", Body: `{"headers":{},"body":""}`},
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if _, ok := e.Match("GET", "", "/admin/config.php"); !ok {
+		t.Error("Match() = false, want true for a path matching the glob")
+	}
+	if _, ok := e.Match("POST", "", "/admin/config.php"); ok {
+		t.Error("Match() = true, want false for a method that doesn't match")
+	}
+	if _, ok := e.Match("GET", "", "/other"); ok {
+		t.Error("Match() = true, want false for a path outside the glob")
+	}
+}
+
+func TestRenderFillsLLMSlotAndDeterministicFields(t *testing.T) {
+	e, err := New([]RouteConfig{
+		{ID: "admin", Body: `{"body":"{{llm "describe an admin panel"}} {{uuid}}"}`},
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+	route, ok := e.Match("GET", "", "/")
+	if !ok {
+		t.Fatal("Match() = false, want true for a route with no constraints")
+	}
+
+	var gotRouteID, gotPrompt string
+	resolve := func(ctx context.Context, routeID, prompt string) (string, error) {
+		gotRouteID, gotPrompt = routeID, prompt
+		return "a login form", nil
+	}
+
+	out, err := e.Render(context.Background(), route, resolve)
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+
+	if gotRouteID != "admin" || gotPrompt != "describe an admin panel" {
+		t.Errorf("resolve called with (%q, %q), want (\"admin\", \"describe an admin panel\")", gotRouteID, gotPrompt)
+	}
+	if !strings.Contains(string(out), "a login form") {
+		t.Errorf("Render() = %q, want it to contain the resolved llm slot", out)
+	}
+
+	var resp struct {
+		Body string `json:"body"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("Render() = %q, not valid JSON: %v", out, err)
+	}
+	if id := strings.TrimPrefix(resp.Body, "a login form "); len(id) != 36 {
+		t.Errorf("Render() body = %q, want the llm slot followed by a 36-character {{uuid}}", resp.Body)
+	}
+}
@@ -0,0 +1,16 @@
+package template
+
+import "encoding/json"
+
+// jsonEscape renders s the way it would appear inside a JSON string
+// literal, without the surrounding quotes, so route bodies can splice
+// {{llm "..."}} and {{file "..."}} output directly into a quoted JSON
+// field (galah's HTTPResponse bodies and header values are JSON) without
+// the author having to hand-escape newlines or quotes themselves.
+func jsonEscape(s string) string {
+	quoted, err := json.Marshal(s)
+	if err != nil {
+		return s
+	}
+	return string(quoted[1 : len(quoted)-1])
+}